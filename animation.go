@@ -0,0 +1,120 @@
+// animation.go plays the tile flip and row shake animations, one frame per tick
+package main
+
+import (
+	"os"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"charm.land/lipgloss/v2"
+)
+
+// animationKind is the kind of animation being played
+type animationKind int
+
+const (
+	animationNone  animationKind = iota
+	animationFlip                // the tiles of a submitted guess turn over one at a time to show their state
+	animationShake               // the current row shakes when a guess is rejected
+)
+
+const (
+	flipFrameDuration  = 80 * time.Millisecond
+	shakeFrameDuration = 50 * time.Millisecond
+	// a tile is edge-on for one frame, then shows its state while the next tile turns
+	flipFramesPerTile = 2
+	// largest horizontal offset of a shaking row, half the gap between boards so the boards stay in place
+	// while their grids are padded
+	shakeAmplitude = len(boardGap) / 2
+)
+
+// shakeOffsets are the horizontal offsets of a shaking row, one per frame
+var shakeOffsets = []int{1, -1, 1, -1, 1, -1, 0}
+
+// animation is the animation being played. Animations only change how the game is drawn: the game is updated
+// before they start, so they can be stopped at any frame and the screen still shows the game as it is.
+type animation struct {
+	kind     animationKind
+	frame    int
+	id       int       // identifies the ticks of the animation, the ticks of a stopped animation are ignored
+	keyboard *keyboard // keyboard as it was before the guess, shown until the tiles have turned over
+}
+
+// animationTickMsg is a message that is sent to advance the animation with the given id by a frame
+type animationTickMsg struct{ id int }
+
+// tick returns a command that sends the tick of the next frame
+func (a animation) tick() tea.Cmd {
+	d := flipFrameDuration
+	if a.kind == animationShake {
+		d = shakeFrameDuration
+	}
+	id := a.id
+	return tea.Tick(d, func(time.Time) tea.Msg {
+		return animationTickMsg{id: id}
+	})
+}
+
+// frames returns the number of frames of the animation for words of the given length
+func (a animation) frames(wordLength int) int {
+	switch a.kind {
+	case animationFlip:
+		return wordLength * flipFramesPerTile
+	case animationShake:
+		return len(shakeOffsets)
+	}
+	return 0
+}
+
+// flipTile returns the style and text of tile col of a row being turned over: the tile keeps the style of a
+// typed letter until its turn, is edge-on for a frame and then shows its state
+func (a animation) flipTile(col int, style, typed lipgloss.Style, text string) (lipgloss.Style, string) {
+	switch phase := a.frame - col*flipFramesPerTile; {
+	case phase < 0:
+		return typed, text
+	case phase == 0:
+		return typed, " "
+	}
+	return style, text
+}
+
+// shakeRow pads a rendered row so it is drawn at its offset in the current frame of a shake.
+// Every row of a shaking grid is padded, so only the shaking row moves.
+func (a animation) shakeRow(line string, shaking bool) string {
+	offset := 0
+	if shaking {
+		offset = shakeOffsets[a.frame]
+	}
+	return lipgloss.NewStyle().PaddingLeft(shakeAmplitude + offset).PaddingRight(shakeAmplitude - offset).Render(line)
+}
+
+// startAnimation starts an animation and returns the command of its first tick, or nil if animations are off.
+// kb is the keyboard shown during a flip.
+func (m *model) startAnimation(kind animationKind, kb *keyboard) tea.Cmd {
+	m.stopAnimation()
+	if !m.animate {
+		return nil
+	}
+	m.anim = animation{kind: kind, id: m.anim.id, keyboard: kb}
+	return m.anim.tick()
+}
+
+// stopAnimation stops the animation being played, if any, and shows the game as it is
+func (m *model) stopAnimation() {
+	m.anim = animation{id: m.anim.id + 1}
+}
+
+// animating reports whether an animation is being played
+func (m model) animating() bool {
+	return m.anim.kind != animationNone
+}
+
+// interactiveOutput reports whether the game is drawn on a terminal that can play animations,
+// rather than on a dumb terminal or into a file or a pipe
+func interactiveOutput() bool {
+	fi, err := os.Stdout.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0 && os.Getenv("TERM") != "dumb"
+}
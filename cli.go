@@ -0,0 +1,478 @@
+// cli.go parses the command line and runs the lexis subcommands
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/log"
+	"github.com/ieroNo47/lexis/engine"
+)
+
+// version is set at build time with -ldflags "-X main.version=..."
+var version = "dev"
+
+var ErrUsage = errors.New("usage error")
+
+const usage = `lexis, a cli word guessing game
+
+Usage:
+  lexis [command] [flags]
+
+Commands:
+  play     play a puzzle (default)
+  daily    play the daily puzzle shared by everyone on the same date
+  stats    print your statistics
+  history  show or clear the answers already played in random games
+  solve    let the solver play a puzzle or every answer in the word list
+  version  print the version
+  help     print this help
+
+Run 'lexis <command> -h' for the flags of a command.
+`
+
+// run runs the subcommand named by the first argument, play if there is none
+func run(args []string, stdout, stderr io.Writer) error {
+	cmd := "play"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd, args = args[0], args[1:]
+	}
+	switch cmd {
+	case "play":
+		return runPlay(cmd, args, stderr)
+	case "daily":
+		return runPlay(cmd, args, stderr)
+	case "history":
+		return runHistory(args, stdout, stderr)
+	case "stats":
+		return runStats(args, stdout, stderr)
+	case "solve":
+		return runSolve(args, stdout, stderr)
+	case "version":
+		if len(args) > 0 {
+			return fmt.Errorf("%w: version takes no arguments", ErrUsage)
+		}
+		_, err := fmt.Fprintln(stdout, "lexis", versionString())
+		return err
+	case "help":
+		_, err := fmt.Fprint(stdout, usage)
+		return err
+	default:
+		return fmt.Errorf("%w: unknown command %q, run 'lexis help' for the list of commands", ErrUsage, cmd)
+	}
+}
+
+// versionString returns the version set at build time, or the module version when installed with go install
+func versionString() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return version
+}
+
+// parseConfig parses the flags of a command on top of the config file.
+// Flags are parsed into their own config and only the ones set explicitly override the config file.
+// register adds the command flags to the flag set.
+func parseConfig(cmd string, args []string, stderr io.Writer, register func(*flag.FlagSet, *config)) (config, map[string]bool, error) {
+	defaultPath, err := defaultConfigPath()
+	if err != nil {
+		return config{}, nil, err
+	}
+	var flags config
+	fs := flag.NewFlagSet("lexis "+cmd, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	configPath := fs.String("config", defaultPath, "path to the config file")
+	register(fs, &flags)
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return config{}, nil, err
+		}
+		return config{}, nil, fmt.Errorf("%w: %w", ErrUsage, err)
+	}
+	if fs.NArg() > 0 {
+		return config{}, nil, fmt.Errorf("%w: unexpected argument %q", ErrUsage, fs.Arg(0))
+	}
+
+	cfg, err := loadConfig(*configPath)
+	if err != nil {
+		return config{}, nil, err
+	}
+	set := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) {
+		set[f.Name] = true
+		switch f.Name {
+		case "provider":
+			cfg.Provider = flags.Provider
+		case "answers":
+			cfg.Answers = flags.Answers
+		case "allowed":
+			cfg.Allowed = flags.Allowed
+		case "hunspell":
+			cfg.Hunspell = flags.Hunspell
+		case "seed":
+			cfg.Seed = flags.Seed
+		case "difficulty":
+			cfg.Difficulty = flags.Difficulty
+		case "url":
+			cfg.ServiceURL = flags.ServiceURL
+		case "tz":
+			cfg.Timezone = flags.Timezone
+		case "length":
+			cfg.WordLength = flags.WordLength
+		case "rows":
+			cfg.Rows = flags.Rows
+		case "boards":
+			cfg.Boards = flags.Boards
+		case "mode":
+			cfg.HardMode = flags.HardMode
+		case "hints":
+			cfg.Hints = flags.Hints
+		case "theme":
+			cfg.Theme = flags.Theme
+		case "lang":
+			cfg.Language = flags.Language
+		case "colorblind":
+			cfg.Colorblind = flags.Colorblind
+		case "reduced-motion":
+			cfg.ReducedMotion = flags.ReducedMotion
+		case "layout":
+			cfg.Layout = flags.Layout
+		case "profile":
+			cfg.Profile = flags.Profile
+		case "history-file":
+			cfg.HistoryFile = flags.HistoryFile
+		case "stats-file":
+			cfg.StatsFile = flags.StatsFile
+		case "log-file":
+			cfg.LogFile = flags.LogFile
+		case "log-level":
+			cfg.LogLevel = flags.LogLevel
+		case "debug":
+			cfg.Debug = flags.Debug
+		}
+	})
+	return cfg, set, nil
+}
+
+// registerGameFlags adds the flags of the commands that start a game
+func registerGameFlags(fs *flag.FlagSet, flags *config) {
+	defaults := defaultConfig()
+	fs.StringVar(&flags.Provider, "provider", defaults.Provider, fmt.Sprintf("answer provider, one of %v", providers))
+	fs.StringVar(&flags.Answers, "answers", defaults.Answers, "path to the answer word list (default: built-in list)")
+	fs.StringVar(&flags.Allowed, "allowed", defaults.Allowed, "path to the allowed-guess word list (default: built-in list)")
+	fs.StringVar(&flags.Hunspell, "hunspell", defaults.Hunspell, "Hunspell dictionary checking the guesses, e.g. en_US or the path of a .dic file (default: the allowed-guess list)")
+	fs.StringVar(&flags.Difficulty, "difficulty", defaults.Difficulty, fmt.Sprintf("how common the random answers are, one of %v (easy and hard need word frequencies in the answer list)", engine.Difficulties))
+	fs.Int64Var(&flags.Seed, "seed", defaults.Seed, "seed for the random provider, to replay the same answers (default: random)")
+	fs.StringVar(&flags.ServiceURL, "url", defaults.ServiceURL, "base URL of the word service of the http provider")
+	fs.StringVar(&flags.Timezone, "tz", defaults.Timezone, "IANA timezone used for the daily puzzle rollover, e.g. Europe/Athens or UTC")
+	fs.IntVar(&flags.WordLength, "length", defaults.WordLength, fmt.Sprintf("number of letters per word (%d-%d)", minWordLength, maxWordLength))
+	fs.IntVar(&flags.Rows, "rows", defaults.Rows, "number of attempts (default 6, or 5 more than the number of boards)")
+	fs.IntVar(&flags.Boards, "boards", defaults.Boards, fmt.Sprintf("number of boards solved at once, one of %v", boardCounts))
+	fs.Func("mode", "game mode, normal or hard (revealed hints must be used in later guesses)", modeFlag(flags))
+	fs.IntVar(&flags.Hints, "hints", defaults.Hints, "number of hints that can be used per game, 0 to disable hints")
+	fs.StringVar(&flags.Theme, "theme", defaults.Theme, fmt.Sprintf("color theme, one of %v or a theme defined in the config", builtinThemeNames()))
+	fs.BoolVar(&flags.Colorblind, "colorblind", defaults.Colorblind, "mark the letter states by shape and use an orange and blue palette")
+	fs.BoolVar(&flags.ReducedMotion, "reduced-motion", defaults.ReducedMotion, "no tile flip and row shake animations")
+	fs.StringVar(&flags.Language, "lang", defaults.Language, fmt.Sprintf("language of the words, one of %v", engine.Languages()))
+	fs.StringVar(&flags.Layout, "layout", defaults.Layout, fmt.Sprintf("keyboard layout, one of %v or a layout defined in the config (default: the layout of the language)", layoutNames()))
+	fs.StringVar(&flags.Profile, "profile", defaults.Profile, "name of the player, random answers do not repeat until the profile has played them all")
+	fs.StringVar(&flags.LogFile, "log-file", defaults.LogFile, "path to the log file (default: no logging)")
+	fs.StringVar(&flags.LogLevel, "log-level", defaults.LogLevel, "minimum level of the logged messages: debug, info, warn or error")
+	fs.BoolVar(&flags.Debug, "debug", defaults.Debug, "show the internal state in the result bar and log the answer (spoils the game)")
+}
+
+// modeFlag returns the parser of the -mode flag, setting hard mode in flags
+func modeFlag(flags *config) func(string) error {
+	return func(s string) error {
+		switch s {
+		case "normal":
+			flags.HardMode = false
+		case "hard":
+			flags.HardMode = true
+		default:
+			return fmt.Errorf("expected normal or hard, got %q", s)
+		}
+		return nil
+	}
+}
+
+// runPlay starts the game, with the daily provider for the daily command unless the http provider is selected
+func runPlay(cmd string, args []string, stderr io.Writer) error {
+	cfg, set, err := parseConfig(cmd, args, stderr, registerGameFlags)
+	if err != nil {
+		return err
+	}
+	if cmd == "daily" {
+		if set["provider"] && !cfg.daily() {
+			return fmt.Errorf("%w: the daily command cannot use -provider %s", ErrUsage, cfg.Provider)
+		}
+		if !cfg.daily() {
+			cfg.Provider = providerDaily
+		}
+	}
+	if set["url"] && cfg.Provider != providerHTTP {
+		return fmt.Errorf("%w: -url only applies to the http provider", ErrUsage)
+	}
+	if set["seed"] && cfg.daily() {
+		return fmt.Errorf("%w: -seed cannot be used with the daily puzzle, everyone gets the same answer", ErrUsage)
+	}
+	if set["difficulty"] && cfg.daily() {
+		return fmt.Errorf("%w: -difficulty cannot be used with the daily puzzle, everyone gets the same answer", ErrUsage)
+	}
+	if cfg.daily() {
+		// the difficulty of the config file only applies to the random games
+		cfg.Difficulty = engine.DifficultyNormal
+	}
+	if set["tz"] && !cfg.daily() {
+		return fmt.Errorf("%w: -tz only applies to the daily puzzle", ErrUsage)
+	}
+	// multi-board games get an extra attempt per board unless the number of attempts is set
+	if cfg.Boards > 1 && !set["rows"] && cfg.Rows == defaultConfig().Rows {
+		cfg.Rows = cfg.Boards + 5
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	// animations are only played on an interactive terminal
+	if !interactiveOutput() {
+		cfg.ReducedMotion = true
+	}
+
+	logger, closeLog, err := newLogger(cfg)
+	if err != nil {
+		return err
+	}
+	//nolint:errcheck
+	defer closeLog()
+	providers, err := newProviders(cfg, logger)
+	if err != nil {
+		return err
+	}
+	checker, err := loadChecker(cfg)
+	if err != nil {
+		return err
+	}
+	if cfg.HistoryFile == "" {
+		if cfg.HistoryFile, err = defaultHistoryPath(); err != nil {
+			return err
+		}
+	}
+	rot, err := newRotation(cfg, providers)
+	if err != nil {
+		return err
+	}
+	if cfg.StatsFile == "" {
+		if cfg.StatsFile, err = defaultStatsPath(); err != nil {
+			return err
+		}
+	}
+	st, err := loadStats(cfg.StatsFile)
+	if err != nil {
+		return err
+	}
+	if cfg.SaveFile == "" {
+		if cfg.SaveFile, err = defaultSavePath(); err != nil {
+			return err
+		}
+	}
+	saved, err := loadSavedGame(cfg.SaveFile)
+	if err != nil {
+		// a broken save file should not prevent playing
+		logger.Error("Failed to load saved game", "err", err)
+	}
+	m, err := newModel(logger, providers, checker, cfg, st, saved, rot)
+	if err != nil {
+		return err
+	}
+	// create a new bubbletea program with our model
+	p := tea.NewProgram(m)
+	logger.Info("==== Starting lexis ====", "version", versionString())
+	// run the program
+	_, err = p.Run()
+	return err
+}
+
+// newLogger creates a logger writing to the configured log file, or discarding everything if there is none.
+// The returned function closes the log file.
+func newLogger(cfg config) (*log.Logger, func() error, error) {
+	level, err := log.ParseLevel(cfg.LogLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+	var w io.Writer = io.Discard
+	closeLog := func() error { return nil }
+	if cfg.LogFile != "" {
+		f, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, err
+		}
+		w, closeLog = f, f.Close
+	}
+	logger := log.NewWithOptions(w, log.Options{
+		ReportTimestamp: true,
+		Level:           level,
+	})
+	return logger, closeLog, nil
+}
+
+// newProviders creates the answer providers selected in the config, one per board.
+// The boards of the http provider share a word service, with the daily provider as fallback.
+func newProviders(cfg config, logger *log.Logger) ([]engine.AnswerProvider, error) {
+	dict, err := engine.LoadDictionaryProvider(cfg.Answers, cfg.Allowed, cfg.WordLength, cfg.alphabet())
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Provider == providerRandom {
+		if err := dict.SetDifficulty(cfg.Difficulty); err != nil {
+			return nil, fmt.Errorf("%w: give the frequency of each answer after the word in the answer list (-answers)", err)
+		}
+	}
+	var service *engine.WordService
+	if cfg.Provider == providerHTTP {
+		if service, err = newWordService(cfg, logger); err != nil {
+			return nil, err
+		}
+	}
+	providers := make([]engine.AnswerProvider, max(cfg.Boards, 1))
+	for i := range providers {
+		switch cfg.Provider {
+		case providerDaily, providerHTTP:
+			location, err := time.LoadLocation(cfg.Timezone)
+			if err != nil {
+				return nil, fmt.Errorf("invalid timezone: %w", err)
+			}
+			dp := engine.NewDailyProvider(dict, location)
+			dp.SetBoard(i, len(providers))
+			providers[i] = dp
+			if service != nil {
+				providers[i] = engine.NewHTTPProvider(service, dp)
+			}
+		default:
+			p := dict.Clone()
+			if cfg.Seed != 0 {
+				// a different seed per board, so the boards do not all get the same answer
+				p.Seed(cfg.Seed + int64(i))
+			}
+			providers[i] = p
+		}
+	}
+	return providers, nil
+}
+
+// newWordService creates the client of the word service of the http provider
+func newWordService(cfg config, logger *log.Logger) (*engine.WordService, error) {
+	timeout, interval, err := cfg.serviceDurations()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.CacheFile == "" {
+		if cfg.CacheFile, err = defaultCachePath(); err != nil {
+			return nil, err
+		}
+	}
+	return engine.NewWordService(engine.ServiceOptions{
+		URL:       cfg.ServiceURL,
+		Timeout:   timeout,
+		Interval:  interval,
+		CachePath: cfg.CacheFile,
+		Logger:    logger,
+	})
+}
+
+// runHistory prints the answers a profile has played in random games, or forgets them with -clear
+func runHistory(args []string, stdout, stderr io.Writer) error {
+	clearHistory := false
+	cfg, _, err := parseConfig("history", args, stderr, func(fs *flag.FlagSet, flags *config) {
+		fs.StringVar(&flags.Profile, "profile", defaultProfile, "name of the player")
+		fs.StringVar(&flags.HistoryFile, "history-file", "", "path to the history file (default: history.json in the user's data directory)")
+		fs.BoolVar(&clearHistory, "clear", false, "forget the answers played, so any answer can come up again")
+	})
+	if err != nil {
+		return err
+	}
+	if cfg.HistoryFile == "" {
+		if cfg.HistoryFile, err = defaultHistoryPath(); err != nil {
+			return err
+		}
+	}
+	h, err := loadHistory(cfg.HistoryFile)
+	if err != nil {
+		return err
+	}
+	if clearHistory {
+		h.clear(cfg.Profile)
+		if err := h.save(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(stdout, "History of profile %s cleared\n", cfg.Profile)
+		return err
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Profile: %s\n", cfg.Profile)
+	lists := h.lists(cfg.Profile)
+	if len(lists) == 0 {
+		sb.WriteString("No answers played yet\n")
+	}
+	for _, list := range lists {
+		played := h.played(cfg.Profile, list)
+		fmt.Fprintf(&sb, "\n%s, %d answers played in this round:\n%s\n", list, len(played), strings.Join(played, " "))
+	}
+	_, err = io.WriteString(stdout, sb.String())
+	return err
+}
+
+// runStats prints the statistics of the finished games
+func runStats(args []string, stdout, stderr io.Writer) error {
+	cfg, _, err := parseConfig("stats", args, stderr, func(fs *flag.FlagSet, flags *config) {
+		fs.StringVar(&flags.StatsFile, "stats-file", "", "path to the stats file (default: stats.json in the user's data directory)")
+		fs.IntVar(&flags.Boards, "boards", defaultConfig().Boards, "show the statistics of the games played with this number of boards")
+	})
+	if err != nil {
+		return err
+	}
+	if cfg.StatsFile == "" {
+		if cfg.StatsFile, err = defaultStatsPath(); err != nil {
+			return err
+		}
+	}
+	st, err := loadStats(cfg.StatsFile)
+	if err != nil {
+		return err
+	}
+	sum := st.summarize(cfg.Rows, cfg.Boards)
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Played:         %d\n", sum.played)
+	fmt.Fprintf(&sb, "Win %%:          %d\n", sum.winPercent())
+	fmt.Fprintf(&sb, "Current streak: %d\n", sum.currentStreak)
+	fmt.Fprintf(&sb, "Max streak:     %d\n", sum.maxStreak)
+	fmt.Fprintf(&sb, "Hints used:     %d\n", sum.hints)
+	sb.WriteString("\nGuess distribution\n")
+	writeDistribution(&sb, sum.distribution)
+	_, err = io.WriteString(stdout, sb.String())
+	return err
+}
+
+// writeDistribution writes a text bar chart of the number of games won in each number of guesses
+func writeDistribution(sb *strings.Builder, distribution []int) {
+	maxCount := 0
+	for _, count := range distribution {
+		maxCount = max(maxCount, count)
+	}
+	for i, count := range distribution {
+		width := 0
+		if maxCount > 0 {
+			width = count * statsBarWidth / maxCount
+		}
+		fmt.Fprintf(sb, "%2d %s %d\n", i+1, strings.Repeat("█", width), count)
+	}
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCLI runs lexis with args, with the config, data and cache directories in a temporary directory,
+// and returns what it printed
+func runCLI(t *testing.T, args ...string) (stdout, stderr string, err error) {
+	t.Helper()
+	dir := t.TempDir()
+	t.Setenv("HOME", dir)
+	t.Setenv("XDG_CONFIG_HOME", filepath.Join(dir, "config"))
+	t.Setenv("XDG_DATA_HOME", filepath.Join(dir, "data"))
+	t.Setenv("XDG_CACHE_HOME", filepath.Join(dir, "cache"))
+	var out, errOut strings.Builder
+	err = run(args, &out, &errOut)
+	return out.String(), errOut.String(), err
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		err    error
+		stderr string // part of what the flag parser printed, empty if it printed nothing
+	}{
+		{"unknown command", []string{"replay"}, ErrUsage, ""},
+		{"version with an argument", []string{"version", "1"}, ErrUsage, ""},
+		{"unknown flag", []string{"stats", "-days", "3"}, ErrUsage, "Usage of lexis stats:"},
+		{"unexpected argument", []string{"stats", "all"}, ErrUsage, ""},
+		{"help", []string{"stats", "-h"}, flag.ErrHelp, "-stats-file"},
+		{"invalid mode", []string{"-mode", "easy"}, ErrUsage, "expected normal or hard"},
+		{"daily with the random provider", []string{"daily", "-provider", "random"}, ErrUsage, ""},
+		{"url without the http provider", []string{"-url", "https://words.example.com"}, ErrUsage, ""},
+		{"seed with the daily puzzle", []string{"daily", "-seed", "3"}, ErrUsage, ""},
+		{"difficulty with the daily puzzle", []string{"daily", "-difficulty", "easy"}, ErrUsage, ""},
+		{"tz without the daily puzzle", []string{"play", "-tz", "Europe/Athens"}, ErrUsage, ""},
+		{"invalid word length", []string{"-length", "2"}, ErrInvalidConfig, ""},
+		{"invalid timezone", []string{"daily", "-tz", "Mars/Olympus"}, ErrInvalidConfig, ""},
+		{"layout of another language", []string{"-lang", "el", "-layout", "qwerty"}, ErrInvalidConfig, ""},
+		{"history with an argument", []string{"history", "default"}, ErrUsage, ""},
+		{"solve without a puzzle", []string{"solve"}, ErrUsage, ""},
+		{"solve with an answer and every answer", []string{"solve", "-answer", "crane", "-all"}, ErrUsage, ""},
+		{"solve with an answer and the daily puzzle", []string{"solve", "-answer", "crane", "-daily"}, ErrUsage, ""},
+		{"solve with an answer and a puzzle number", []string{"solve", "-answer", "crane", "-puzzle", "3"}, ErrUsage, ""},
+		{"solve with tz without the daily puzzle", []string{"solve", "-answer", "crane", "-tz", "UTC"}, ErrUsage, ""},
+		{"solve an unknown answer", []string{"solve", "-answer", "zzzzz"}, ErrUsage, ""},
+		{"solve with an unknown opener", []string{"solve", "-answer", "crane", "-opener", "zzzzz"}, ErrUsage, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, stderr, err := runCLI(t, tt.args...)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("run(%q) = %v, want %v", tt.args, err, tt.err)
+			}
+			if !strings.Contains(stderr, tt.stderr) || (tt.stderr == "" && stderr != "") {
+				t.Errorf("run(%q) printed %q to stderr, want %q", tt.args, stderr, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRunOutput(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want []string // parts of the output
+	}{
+		{"version", []string{"version"}, []string{"lexis "}},
+		{"help", []string{"help"}, []string{"Usage:", "solve"}},
+		{"stats without games", []string{"stats"}, []string{"Played:         0\n", "Win %:          0\n", " 6  0\n"}},
+		{"history without games", []string{"history"}, []string{"Profile: default\n", "No answers played yet\n"}},
+		{"solve an answer", []string{"solve", "-answer", "crane"}, []string{"lexis\n", "Solved in "}},
+		{"solve with an opener", []string{"solve", "-answer", "crane", "-opener", "slate"}, []string{"1 slate ", "Solved in "}},
+		{"solve a daily puzzle", []string{"solve", "-puzzle", "12", "-tz", "UTC"}, []string{"lexis #12\n", "Solved in "}},
+		{"solve in greek", []string{"solve", "-lang", "el", "-answer", "λόγος"}, []string{"Solved in "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := runCLI(t, tt.args...)
+			if err != nil {
+				t.Fatalf("run(%q) = %v", tt.args, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("run(%q) printed %q, want it to contain %q", tt.args, out, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunStats(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "stats.json")
+	s := stats{path: path}
+	for _, r := range []gameRecord{record(3, 1), record(0, 1), record(4, 1), record(2, 1), record(7, 2)} {
+		s.add(r)
+	}
+	if err := s.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	tests := []struct {
+		name string
+		args []string
+		want []string
+	}{
+		{"single board", []string{"stats", "-stats-file", path}, []string{
+			"Played:         4\n", "Win %:          75\n", "Current streak: 2\n", "Max streak:     2\n", "Hints used:     4\n",
+			" 2 " + strings.Repeat("█", statsBarWidth) + " 1\n", " 5  0\n",
+		}},
+		{"two boards", []string{"stats", "-stats-file", path, "-boards", "2"}, []string{"Played:         1\n", "Win %:          100\n", " 7 "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, _, err := runCLI(t, tt.args...)
+			if err != nil {
+				t.Fatalf("run(%q) = %v", tt.args, err)
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out, want) {
+					t.Errorf("run(%q) printed %q, want it to contain %q", tt.args, out, want)
+				}
+			}
+		})
+	}
+}
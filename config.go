@@ -0,0 +1,275 @@
+// config.go loads the user settings from the config file
+package main
+
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/log"
+	"github.com/ieroNo47/lexis/engine"
+)
+
+const (
+	// word length bounds, matching the built-in word lists
+	minWordLength = 3
+	maxWordLength = 10
+)
+
+// answer providers that can be selected in the config
+const (
+	providerRandom = engine.ModeRandom
+	providerDaily  = engine.ModeDaily
+	providerHTTP   = "http" // daily puzzle from a word service, see engine.WordService
+)
+
+var providers = []string{providerRandom, providerDaily, providerHTTP}
+
+// number of boards that can be played at once
+var boardCounts = []int{1, 2, 4, 8}
+
+// profile used when none is selected
+const defaultProfile = "default"
+
+var ErrInvalidConfig = errors.New("invalid config")
+
+// config holds the user settings. Values are read from the config file and can be overridden by command-line flags.
+type config struct {
+	WordLength      int                 `json:"wordLength"`      // number of letters per word
+	Rows            int                 `json:"rows"`            // number of attempts
+	Boards          int                 `json:"boards"`          // number of boards solved at once, each with its own answer
+	HardMode        bool                `json:"hardMode"`        // revealed hints must be used in later guesses
+	Hints           int                 `json:"hints"`           // number of hints that can be used per game
+	Language        string              `json:"language"`        // language of the words and the built-in word lists
+	Provider        string              `json:"provider"`        // answer provider, random, daily or http
+	ServiceURL      string              `json:"serviceUrl"`      // base URL of the word service of the http provider
+	ServiceTimeout  string              `json:"serviceTimeout"`  // timeout of a word service request, e.g. 3s
+	ServiceInterval string              `json:"serviceInterval"` // minimum time between two word service requests, e.g. 250ms
+	CacheFile       string              `json:"cacheFile"`       // path to the word service cache, empty for words.json in the user's cache directory
+	Difficulty      string              `json:"difficulty"`      // how common the random answers are: easy, normal or hard
+	Seed            int64               `json:"seed"`            // seed for the random provider, 0 for a different answer every game
+	Timezone        string              `json:"timezone"`        // IANA timezone used for the daily puzzle rollover
+	Answers         string              `json:"answers"`         // path to the answer word list, empty for the built-in list
+	Allowed         string              `json:"allowed"`         // path to the allowed-guess word list, empty for the built-in list
+	Hunspell        string              `json:"hunspell"`        // Hunspell dictionary checking the guesses, a name such as en_US or the path of its .dic file
+	Theme           string              `json:"theme"`           // color theme
+	Colorblind      bool                `json:"colorblind"`      // mark the letter states by shape and use an orange and blue palette
+	ReducedMotion   bool                `json:"reducedMotion"`   // no tile flip and row shake animations
+	Themes          map[string]theme    `json:"themes"`          // user-defined color themes by name
+	Layout          string              `json:"layout"`          // keyboard layout shown on screen, empty for the layout of the language
+	Layouts         map[string][]string `json:"layouts"`         // user-defined keyboard layouts, rows of letter keys by name
+	Profile         string              `json:"profile"`         // name of the player, each profile has its own history of random answers
+	HistoryFile     string              `json:"historyFile"`     // path to the history file, empty for history.json in the user's data directory
+	StatsFile       string              `json:"statsFile"`       // path to the stats file, empty for stats.json in the user's data directory
+	SaveFile        string              `json:"saveFile"`        // path to the save file, empty for save.json in the user's data directory
+	LogFile         string              `json:"logFile"`         // path to the log file, empty to disable logging
+	LogLevel        string              `json:"logLevel"`        // minimum level of the logged messages
+	Debug           bool                `json:"debug"`           // show the internal state and log the answer, for development only
+}
+
+func defaultConfig() config {
+	return config{
+		WordLength: 5,
+		Rows:       6,
+		Boards:     1,
+		Hints:      3,
+		Language:   engine.Latin.Language,
+		Provider:   providerRandom,
+		Difficulty: engine.DifficultyNormal,
+		Profile:    defaultProfile,
+		Timezone:   "Local",
+		Theme:      defaultTheme,
+		LogLevel:   "info",
+		// durations are strings in the config file
+		ServiceTimeout:  engine.DefaultServiceTimeout.String(),
+		ServiceInterval: engine.DefaultServiceInterval.String(),
+	}
+}
+
+// defaultConfigPath returns the path of the config file in the user's config directory
+func defaultConfigPath() (string, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lexis", "config.json"), nil
+}
+
+// defaultCachePath returns the path of the word service cache in the user's cache directory
+func defaultCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "lexis", "words.json"), nil
+}
+
+// loadConfig reads the config file at path on top of the default settings.
+// A missing file is not an error and returns the defaults.
+func loadConfig(path string) (config, error) {
+	cfg := defaultConfig()
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// validate checks that the settings are within the supported ranges
+func (c config) validate() error {
+	if c.WordLength < minWordLength || c.WordLength > maxWordLength {
+		return fmt.Errorf("%w: word length must be between %d and %d, got %d", ErrInvalidConfig, minWordLength, maxWordLength, c.WordLength)
+	}
+	if c.Rows < 1 {
+		return fmt.Errorf("%w: rows must be at least 1, got %d", ErrInvalidConfig, c.Rows)
+	}
+	if !slices.Contains(boardCounts, c.Boards) {
+		return fmt.Errorf("%w: boards must be one of %v, got %d", ErrInvalidConfig, boardCounts, c.Boards)
+	}
+	if c.Hints < 0 {
+		return fmt.Errorf("%w: hints cannot be negative, got %d", ErrInvalidConfig, c.Hints)
+	}
+	if _, ok := engine.AlphabetFor(c.Language); !ok {
+		return fmt.Errorf("%w: unknown language %q, expected one of %v", ErrInvalidConfig, c.Language, engine.Languages())
+	}
+	if !slices.Contains(providers, c.Provider) {
+		return fmt.Errorf("%w: unknown provider %q, expected one of %v", ErrInvalidConfig, c.Provider, providers)
+	}
+	if c.Provider == providerHTTP {
+		if u, err := url.Parse(c.ServiceURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: the http provider needs the http or https URL of a word service, got %q", ErrInvalidConfig, c.ServiceURL)
+		}
+	}
+	if _, _, err := c.serviceDurations(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	if !slices.Contains(engine.Difficulties, c.Difficulty) {
+		return fmt.Errorf("%w: unknown difficulty %q, expected one of %v", ErrInvalidConfig, c.Difficulty, engine.Difficulties)
+	}
+	if c.daily() && c.Seed != 0 {
+		return fmt.Errorf("%w: a seed cannot be used with the daily provider, everyone gets the same puzzle", ErrInvalidConfig)
+	}
+	if _, err := time.LoadLocation(c.Timezone); err != nil {
+		return fmt.Errorf("%w: invalid timezone: %w", ErrInvalidConfig, err)
+	}
+	for name, t := range c.Themes {
+		if err := validateTheme(t); err != nil {
+			return fmt.Errorf("%w: theme %q: %w", ErrInvalidConfig, name, err)
+		}
+	}
+	if _, ok := c.themes()[c.Theme]; !ok {
+		return fmt.Errorf("%w: unknown theme %q, expected one of %v or a theme defined in the config", ErrInvalidConfig, c.Theme, builtinThemeNames())
+	}
+	for name, rows := range c.Layouts {
+		if err := validateLayout(rows); err != nil {
+			return fmt.Errorf("%w: keyboard layout %q: %w", ErrInvalidConfig, name, err)
+		}
+	}
+	if c.keyboardRows() == nil {
+		return fmt.Errorf("%w: unknown keyboard layout %q, expected one of %v or a layout defined in the config", ErrInvalidConfig, c.Layout, layoutNames())
+	}
+	if err := validateLayoutLetters(c.keyboardRows(), c.alphabet()); err != nil {
+		return fmt.Errorf("%w: keyboard layout %q cannot type %s words: %w", ErrInvalidConfig, cmp.Or(c.Layout, languageLayouts[c.Language]), c.Language, err)
+	}
+	if c.Profile == "" {
+		return fmt.Errorf("%w: the profile name cannot be empty", ErrInvalidConfig)
+	}
+	if _, err := log.ParseLevel(c.LogLevel); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidConfig, err)
+	}
+	return nil
+}
+
+// daily reports whether the selected provider plays the daily puzzle
+func (c config) daily() bool {
+	return c.Provider == providerDaily || c.Provider == providerHTTP
+}
+
+// serviceDurations returns the timeout of a word service request and the minimum time between two requests
+func (c config) serviceDurations() (timeout, interval time.Duration, err error) {
+	if timeout, err = time.ParseDuration(c.ServiceTimeout); err != nil || timeout <= 0 {
+		return 0, 0, fmt.Errorf("service timeout must be a positive duration, got %q", c.ServiceTimeout)
+	}
+	if interval, err = time.ParseDuration(c.ServiceInterval); err != nil || interval < 0 {
+		return 0, 0, fmt.Errorf("service interval must be a duration, got %q", c.ServiceInterval)
+	}
+	return timeout, interval, nil
+}
+
+// keyboardRows returns the rows of the selected keyboard layout, looking in the user-defined layouts first,
+// or nil if there is no layout with that name. Without a selected layout, the layout of the language is used.
+func (c config) keyboardRows() []string {
+	name := cmp.Or(c.Layout, languageLayouts[c.Language])
+	if rows, ok := c.Layouts[name]; ok {
+		return rows
+	}
+	return keyboardLayouts[name]
+}
+
+// themes returns the built-in and user-defined themes by name, a user-defined theme replacing a built-in one
+// with the same name
+func (c config) themes() map[string]theme {
+	themes := maps.Clone(builtinThemes)
+	for name, t := range c.Themes {
+		themes[name] = t.resolve()
+	}
+	return themes
+}
+
+// alphabet returns the alphabet of the selected language
+func (c config) alphabet() engine.Alphabet {
+	a, _ := engine.AlphabetFor(c.Language)
+	return a
+}
+
+// validateLayout checks that a keyboard layout has at least one row and only lowercase letters, each at most once
+func validateLayout(rows []string) error {
+	if len(rows) == 0 {
+		return errors.New("no rows")
+	}
+	seen := map[rune]bool{}
+	for _, row := range rows {
+		for _, r := range row {
+			if !unicode.IsLower(r) {
+				return fmt.Errorf("%q is not a lowercase letter", r)
+			}
+			if seen[r] {
+				return fmt.Errorf("%q appears more than once", r)
+			}
+			seen[r] = true
+		}
+	}
+	return nil
+}
+
+// validateLayoutLetters checks that a keyboard layout has a key for every letter of the alphabet, variants being typed
+// with the key of their base letter, and no key for a letter of another alphabet
+func validateLayoutLetters(rows []string, a engine.Alphabet) error {
+	keys := []rune(strings.Join(rows, ""))
+	for _, r := range keys {
+		if !slices.Contains(a.Letters, r) {
+			return fmt.Errorf("%q is not a letter of the alphabet", r)
+		}
+	}
+	for _, r := range a.Runes() {
+		if !slices.Contains(keys, a.FoldRune(r)) {
+			return fmt.Errorf("no key for %q", r)
+		}
+	}
+	return nil
+}
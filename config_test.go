@@ -0,0 +1,40 @@
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidateLayout(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		layout   string
+		layouts  map[string][]string
+		ok       bool
+	}{
+		{"english default", "en", "", nil, true},
+		{"english dvorak", "en", "dvorak", nil, true},
+		{"english azerty", "en", "azerty", nil, true},
+		{"greek default", "el", "", nil, true},
+		{"greek layout for english", "en", "greek", nil, false},
+		{"english layout for greek", "el", "qwerty", nil, false},
+		{"own layout", "en", "mine", map[string][]string{"mine": {"abcdefghijklm", "nopqrstuvwxyz"}}, true},
+		{"own layout missing a letter", "en", "mine", map[string][]string{"mine": {"qwertyuiop", "asdfghjkl", "zxcvbn"}}, false},
+		{"own layout with a variant", "el", "mine", map[string][]string{"mine": {"ερτυθιοπ", "ασδφγηξκλ", "ζχψωβνμς"}}, false},
+		{"own layout replacing the default", "el", "", map[string][]string{"greek": {"qwertyuiop", "asdfghjkl", "zxcvbnm"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := defaultConfig()
+			cfg.Language, cfg.Layout, cfg.Layouts = tt.language, tt.layout, tt.layouts
+			err := cfg.validate()
+			if tt.ok && err != nil {
+				t.Errorf("validate() = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrInvalidConfig) {
+				t.Errorf("validate() = %v, want %v", err, ErrInvalidConfig)
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+// alphabet.go defines the letters of each supported language and how their variants are folded when scoring
+package engine
+
+import (
+	"maps"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Alphabet is the set of letters a language is played with.
+// Variants of a letter, such as accented letters or final forms, are folded to their base letter
+// when words are compared, but are kept as typed when words are displayed.
+type Alphabet struct {
+	Language string        // language code, also the directory of the built-in word lists
+	Letters  []rune        // base letters, most common first
+	variants map[rune]rune // variant letters and the base letter they fold to
+	final    map[rune]rune // base letters and the form they take at the end of a word
+}
+
+// Latin is the alphabet of the English word lists
+var Latin = Alphabet{
+	Language: "en",
+	Letters:  []rune("etaoinshrdlcumwfgypbvkjxqz"),
+}
+
+// Greek is the monotonic Greek alphabet: accents and diaeresis are ignored when scoring and
+// final sigma is the same letter as sigma
+var Greek = Alphabet{
+	Language: "el",
+	Letters:  []rune("αοειτνσρηκπμλυωδγχθφβξζψ"),
+	variants: map[rune]rune{
+		'ά': 'α', 'έ': 'ε', 'ή': 'η', 'ί': 'ι', 'ϊ': 'ι', 'ΐ': 'ι',
+		'ό': 'ο', 'ύ': 'υ', 'ϋ': 'υ', 'ΰ': 'υ', 'ώ': 'ω', 'ς': 'σ',
+	},
+	final: map[rune]rune{'σ': 'ς'},
+}
+
+// alphabets holds the supported alphabets by language code
+var alphabets = map[string]Alphabet{
+	Latin.Language: Latin,
+	Greek.Language: Greek,
+}
+
+// AlphabetFor returns the alphabet of a language code
+func AlphabetFor(language string) (Alphabet, bool) {
+	a, ok := alphabets[language]
+	return a, ok
+}
+
+// Languages returns the codes of the supported languages in alphabetical order
+func Languages() []string {
+	return slices.Sorted(maps.Keys(alphabets))
+}
+
+// Runes returns every letter that can be typed: the base letters in alphabetical order followed by their variants
+func (a Alphabet) Runes() []rune {
+	rs := slices.Sorted(slices.Values(a.Letters))
+	return append(rs, slices.Sorted(maps.Keys(a.variants))...)
+}
+
+// FoldRune returns the lowercase base letter of r
+func (a Alphabet) FoldRune(r rune) rune {
+	r = unicode.ToLower(r)
+	if base, ok := a.variants[r]; ok {
+		return base
+	}
+	return r
+}
+
+// Fold returns word with every letter folded to its lowercase base letter, the form words are compared in
+func (a Alphabet) Fold(word string) string {
+	return strings.Map(a.FoldRune, word)
+}
+
+// Contains reports whether r is a letter of the alphabet or one of its variants, in any case
+func (a Alphabet) Contains(r rune) bool {
+	return slices.Contains(a.Letters, a.FoldRune(r))
+}
+
+// Display returns word with its last letter in its final form, if the alphabet has one,
+// and the final forms used inside the word replaced by their base letter
+func (a Alphabet) Display(word string) string {
+	rs := []rune(word)
+	for i, r := range rs {
+		if base, ok := a.variants[r]; ok && a.final[base] == r && i < len(rs)-1 {
+			rs[i] = base
+		}
+	}
+	if n := len(rs); n > 0 {
+		if f, ok := a.final[rs[n-1]]; ok {
+			rs[n-1] = f
+		}
+	}
+	return string(rs)
+}
+
+// orDefault returns the alphabet, or Latin if it is the zero value
+func (a Alphabet) orDefault() Alphabet {
+	if a.Letters == nil {
+		return Latin
+	}
+	return a
+}
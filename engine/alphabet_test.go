@@ -0,0 +1,128 @@
+package engine
+
+import "testing"
+
+func TestFoldRune(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet Alphabet
+		r        rune
+		want     rune
+	}{
+		{"latin lowercase", Latin, 'a', 'a'},
+		{"latin uppercase", Latin, 'A', 'a'},
+		{"latin accents are not folded", Latin, 'é', 'é'},
+		{"greek base letter", Greek, 'α', 'α'},
+		{"greek uppercase", Greek, 'Ω', 'ω'},
+		{"greek accent", Greek, 'ά', 'α'},
+		{"greek uppercase accent", Greek, 'Ή', 'η'},
+		{"greek diaeresis", Greek, 'ϊ', 'ι'},
+		{"greek diaeresis and accent", Greek, 'ΰ', 'υ'},
+		{"greek final sigma", Greek, 'ς', 'σ'},
+		{"greek uppercase sigma", Greek, 'Σ', 'σ'},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.alphabet.FoldRune(tt.r); got != tt.want {
+				t.Errorf("FoldRune(%q) = %q, want %q", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFold(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet Alphabet
+		word     string
+		want     string
+	}{
+		{"latin", Latin, "Crane", "crane"},
+		{"greek accents", Greek, "άλογο", "αλογο"},
+		{"greek diaeresis", Greek, "Ϊδρυσ", "ιδρυσ"},
+		{"greek final sigma", Greek, "λόγος", "λογοσ"},
+		{"greek middle sigma", Greek, "κόσμε", "κοσμε"},
+		{"greek final sigma in the middle", Greek, "κόςμε", "κοσμε"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.alphabet.Fold(tt.word); got != tt.want {
+				t.Errorf("Fold(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisplay(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet Alphabet
+		word     string
+		want     string
+	}{
+		{"latin", Latin, "crane", "crane"},
+		{"greek final sigma", Greek, "λογοσ", "λογος"},
+		{"greek final sigma kept", Greek, "λόγος", "λόγος"},
+		{"greek middle sigma", Greek, "κόσμε", "κόσμε"},
+		{"greek final sigma in the middle", Greek, "κόςμε", "κόσμε"},
+		{"greek sigmas in the middle and at the end", Greek, "ςεισς", "σεισς"},
+		{"empty", Greek, "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.alphabet.Display(tt.word); got != tt.want {
+				t.Errorf("Display(%q) = %q, want %q", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestContains(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet Alphabet
+		r        rune
+		want     bool
+	}{
+		{"latin", Latin, 'Q', true},
+		{"latin accent", Latin, 'é', false},
+		{"greek letter in latin", Latin, 'α', false},
+		{"greek accent", Greek, 'Ώ', true},
+		{"greek final sigma", Greek, 'ς', true},
+		{"latin letter in greek", Greek, 'a', false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.alphabet.Contains(tt.r); got != tt.want {
+				t.Errorf("Contains(%q) = %v, want %v", tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScoreDisplay(t *testing.T) {
+	tests := []struct {
+		name   string
+		answer string
+		guess  string
+		want   string // letters of the feedback
+	}{
+		{"matched letters take the accents of the answer", "λόγος", "λογοσ", "λόγος"},
+		{"other letters are kept as typed", "λόγος", "άλογο", "άλογο"},
+		{"final sigma typed in the middle", "κόσμε", "κοςμε", "κόσμε"},
+		{"sigma typed at the end of a wrong guess", "λόγος", "νομοσ", "νόμος"},
+		{"sigma not matched at the end", "κόσμε", "μισοσ", "μισος"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t, Greek, []string{tt.answer}, Options{Rows: 6})
+			fb, err := g.Apply(tt.guess)
+			if err != nil {
+				t.Fatalf("Apply(%q): %v", tt.guess, err)
+			}
+			if got := fb.Word(); got != tt.want {
+				t.Errorf("Apply(%q) = %q, want %q", tt.guess, got, tt.want)
+			}
+		})
+	}
+}
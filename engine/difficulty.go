@@ -0,0 +1,145 @@
+// difficulty.go picks random answers by how common they are, from the word frequencies of the answer list
+package engine
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+)
+
+// answer difficulties, from the most common answers to the rarest
+const (
+	DifficultyEasy   = "easy"   // the most common third of the answers, the more common the more likely
+	DifficultyNormal = "normal" // any answer, all equally likely
+	DifficultyHard   = "hard"   // the rarest third of the answers, the rarer the more likely
+)
+
+var Difficulties = []string{DifficultyEasy, DifficultyNormal, DifficultyHard}
+
+var (
+	ErrUnknownDifficulty = errors.New("unknown difficulty")
+	ErrNoFrequencies     = errors.New("the answer list has no word frequencies")
+)
+
+// DifficultyProvider is implemented by providers that pick their answers at a difficulty
+type DifficultyProvider interface {
+	Difficulty() string
+}
+
+// SetFrequencies sets the frequency of each answer, such as its number of occurrences per million words,
+// in the order of the answer list. Answers with a frequency of 0 count as the rarest.
+func (p *DictionaryProvider) SetFrequencies(freqs []float64) error {
+	if len(freqs) != len(p.answers) {
+		return fmt.Errorf("%w: %d frequencies for %d answers", ErrInvalidEntry, len(freqs), len(p.answers))
+	}
+	rarest := 0.0
+	for _, f := range freqs {
+		if f < 0 {
+			return fmt.Errorf("%w: negative frequency %v", ErrInvalidEntry, f)
+		}
+		if f > 0 && (rarest == 0 || f < rarest) {
+			rarest = f
+		}
+	}
+	if rarest == 0 {
+		p.freqs = nil
+		return p.SetDifficulty(p.Difficulty())
+	}
+	p.freqs = make([]float64, len(freqs))
+	for i, f := range freqs {
+		if f == 0 {
+			f = rarest / 2
+		}
+		p.freqs[i] = f
+	}
+	return p.SetDifficulty(p.Difficulty())
+}
+
+// Frequency returns the frequency of an answer, 0 if it is unknown
+func (p DictionaryProvider) Frequency(word string) float64 {
+	if i := slices.Index(p.answers, word); i >= 0 && p.freqs != nil {
+		return p.freqs[i]
+	}
+	return 0
+}
+
+// Difficulty returns the difficulty of the answers
+func (p DictionaryProvider) Difficulty() string {
+	return cmp.Or(p.difficulty, DifficultyNormal)
+}
+
+// SetDifficulty picks the answers at a difficulty. Easy and hard need the frequencies of the answers.
+func (p *DictionaryProvider) SetDifficulty(d string) error {
+	if d == DifficultyNormal {
+		p.difficulty, p.pool, p.weights = d, nil, nil
+		return nil
+	}
+	if !slices.Contains(Difficulties, d) {
+		return fmt.Errorf("%w: %q, expected one of %v", ErrUnknownDifficulty, d, Difficulties)
+	}
+	if p.freqs == nil {
+		return fmt.Errorf("%s difficulty: %w", d, ErrNoFrequencies)
+	}
+	all := make([]int, len(p.answers))
+	for i := range all {
+		all[i] = i
+	}
+	// most common first
+	slices.SortStableFunc(all, func(a, b int) int { return cmp.Compare(p.freqs[b], p.freqs[a]) })
+	third := max(len(all)/3, 1)
+	pool := all[:third]
+	if d == DifficultyHard {
+		pool = all[len(all)-third:]
+	}
+	weights := make([]float64, len(pool))
+	for i, a := range pool {
+		weights[i] = p.freqs[a]
+		if d == DifficultyHard {
+			weights[i] = 1 / p.freqs[a]
+		}
+	}
+	p.difficulty, p.pool, p.weights = d, pool, weights
+	return nil
+}
+
+// pickAnswer returns a random answer of the pool of the difficulty that has not been played yet
+func (p *DictionaryProvider) pickAnswer() string {
+	r := rand.Float64
+	if p.rng != nil {
+		r = p.rng.Float64
+	}
+	pool, weights := p.pool, p.weights
+	if pool == nil {
+		// normal difficulty, every answer is in the pool
+		pool = make([]int, len(p.answers))
+		for i := range pool {
+			pool[i] = i
+		}
+	}
+	if p.history != nil {
+		pool, weights = p.history.unplayed(p.answers, pool, weights)
+	}
+	return p.answers[pool[weightedIndex(len(pool), weights, r())]]
+}
+
+// weightedIndex returns the index of r, a random number in [0, 1), among n items with the given weights,
+// all items having the same weight if weights is nil
+func weightedIndex(n int, weights []float64, r float64) int {
+	if weights == nil {
+		return min(int(r*float64(n)), n-1)
+	}
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	target := r * total
+	for i, w := range weights {
+		if target < w {
+			return i
+		}
+		target -= w
+	}
+	return n - 1
+}
@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestWeightedIndex(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int
+		weights []float64
+		r       float64
+		want    int
+	}{
+		{"uniform first", 4, nil, 0, 0},
+		{"uniform middle", 4, nil, 0.5, 2},
+		{"uniform last", 4, nil, 0.99, 3},
+		{"weighted first", 3, []float64{1, 2, 1}, 0, 0},
+		{"weighted boundary goes to the next item", 3, []float64{1, 2, 1}, 0.25, 1},
+		{"weighted heavy item", 3, []float64{1, 2, 1}, 0.7, 1},
+		{"weighted last", 3, []float64{1, 2, 1}, 0.75, 2},
+		{"zero weight is never picked", 3, []float64{0, 1, 0}, 0, 1},
+		{"all zero weights", 2, []float64{0, 0}, 0.5, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := weightedIndex(tt.n, tt.weights, tt.r); got != tt.want {
+				t.Errorf("weightedIndex(%d, %v, %v) = %d, want %d", tt.n, tt.weights, tt.r, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetDifficulty(t *testing.T) {
+	answers := []string{"pious", "crane", "those", "slate", "geese", "tipsy"}
+	tests := []struct {
+		name        string
+		freqs       []float64 // nil for a list without frequencies
+		difficulty  string
+		want        []string // answers of the pool, most common first, nil for every answer
+		wantWeights []float64
+		err         error
+	}{
+		{"easy", []float64{40, 60, 20, 50, 10, 30}, DifficultyEasy, []string{"crane", "slate"}, []float64{60, 50}, nil},
+		{"hard", []float64{40, 60, 20, 50, 10, 30}, DifficultyHard, []string{"those", "geese"}, []float64{1.0 / 20, 1.0 / 10}, nil},
+		{"normal", []float64{40, 60, 20, 50, 10, 30}, DifficultyNormal, nil, nil, nil},
+		{"unknown frequency is the rarest", []float64{40, 60, 20, 50, 0, 30}, DifficultyHard, []string{"those", "geese"}, []float64{1.0 / 20, 1.0 / 10}, nil},
+		{"no frequencies", nil, DifficultyEasy, nil, nil, ErrNoFrequencies},
+		{"no frequencies at normal", nil, DifficultyNormal, nil, nil, nil},
+		{"unknown difficulty", []float64{40, 60, 20, 50, 10, 30}, "medium", nil, nil, ErrUnknownDifficulty},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := NewDictionaryProvider(answers, nil, Latin)
+			if err != nil {
+				t.Fatalf("NewDictionaryProvider: %v", err)
+			}
+			if tt.freqs != nil {
+				if err := p.SetFrequencies(tt.freqs); err != nil {
+					t.Fatalf("SetFrequencies: %v", err)
+				}
+			}
+			err = p.SetDifficulty(tt.difficulty)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("SetDifficulty(%q) = %v, want %v", tt.difficulty, err, tt.err)
+			}
+			if err != nil {
+				if p.Difficulty() != DifficultyNormal {
+					t.Errorf("Difficulty() = %q after an error, want %q", p.Difficulty(), DifficultyNormal)
+				}
+				return
+			}
+			if p.Difficulty() != tt.difficulty {
+				t.Errorf("Difficulty() = %q, want %q", p.Difficulty(), tt.difficulty)
+			}
+			var got []string
+			for _, i := range p.pool {
+				got = append(got, p.answers[i])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("pool = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(p.weights, tt.wantWeights) {
+				t.Errorf("weights = %v, want %v", p.weights, tt.wantWeights)
+			}
+		})
+	}
+}
+
+func TestBuiltInDifficulties(t *testing.T) {
+	lists := []struct {
+		alphabet Alphabet
+		lengths  []int
+	}{
+		{Latin, []int{3, 4, 5, 6, 7, 8, 9, 10}},
+		{Greek, []int{5}},
+	}
+	for _, l := range lists {
+		for _, n := range l.lengths {
+			p, err := LoadDictionaryProvider("", "", n, l.alphabet)
+			if err != nil {
+				t.Fatalf("LoadDictionaryProvider(%s, %d): %v", l.alphabet.Language, n, err)
+			}
+			for _, d := range Difficulties {
+				if err := p.SetDifficulty(d); err != nil {
+					t.Errorf("%s/answers-%d.txt: SetDifficulty(%q) = %v", l.alphabet.Language, n, d, err)
+				}
+			}
+		}
+	}
+}
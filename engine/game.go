@@ -0,0 +1,474 @@
+// Package engine implements the lexis game rules without any user interface,
+// so they can be reused by the terminal UI, bots, servers and tests.
+package engine
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"unicode"
+
+	"github.com/charmbracelet/log"
+)
+
+// State is the state of a game
+type State int
+
+const (
+	Loading State = iota // the answer has not been picked yet
+	Playing
+	Won
+	Lost
+)
+
+// game modes
+const (
+	ModeRandom = "random"
+	ModeDaily  = "daily"
+)
+
+var (
+	ErrNotResumable = errors.New("game cannot be resumed")
+	ErrNotPlaying   = errors.New("game is not in progress")
+	ErrInvalidWord  = errors.New("invalid word")
+	ErrRowNotFull   = errors.New("row not full")
+	ErrWordLength   = errors.New("word length does not match grid")
+	// hard mode errors
+	ErrHardModeGreen  = errors.New("revealed letter not kept in position")
+	ErrHardModeYellow = errors.New("revealed letter not used")
+)
+
+// HardModeError is returned when a guess in hard mode does not use a hint revealed by an earlier guess
+type HardModeError struct {
+	Err      error // ErrHardModeGreen or ErrHardModeYellow
+	Letter   rune
+	Position int // 1-based position of a green letter
+}
+
+func (e HardModeError) Error() string {
+	if e.Err == ErrHardModeGreen {
+		return fmt.Sprintf("must use %c in position %d", unicode.ToUpper(e.Letter), e.Position)
+	}
+	return fmt.Sprintf("must use %c", unicode.ToUpper(e.Letter))
+}
+
+func (e HardModeError) Unwrap() error {
+	return e.Err
+}
+
+// Options configures a new game
+type Options struct {
+	Rows       int         // number of attempts
+	WordLength int         // number of letters per word, 0 for the provider's word length
+	HardMode   bool        // revealed hints must be used in later guesses
+	Hints      int         // number of hints that can be used per game
+	Checker    WordChecker // checks the guesses instead of the answer provider, nil for the provider
+	Debug      bool        // log the answer, for development only
+	Logger     *log.Logger // nil to disable logging
+}
+
+// Game holds the state of a single game: the answer, the submitted guesses and the letters revealed so far
+type Game struct {
+	provider   AnswerProvider
+	answer     []rune
+	folded     []rune // answer folded to the base letters of the alphabet, the form guesses are compared with
+	alphabet   Alphabet
+	state      State
+	guesses    []Feedback
+	keys       map[rune]LetterState // best state revealed for each guessed letter
+	rows       int
+	length     int
+	hardMode   bool
+	hints      []Hint
+	hintBudget int
+	checker    WordChecker // checks the guesses, nil for the provider
+	debug      bool        // whether the answer may be logged
+	log        *log.Logger
+}
+
+// NewGame creates a new game using the given answer provider.
+// It returns an error if the provider's word length does not match the requested one.
+func NewGame(provider AnswerProvider, opts Options) (*Game, error) {
+	length := provider.WordLength()
+	if opts.WordLength != 0 && opts.WordLength != length {
+		return nil, fmt.Errorf("%w: provider has %d-letter words, grid has %d columns", ErrWordLength, length, opts.WordLength)
+	}
+	if opts.Rows < 1 {
+		return nil, fmt.Errorf("rows must be at least 1, got %d", opts.Rows)
+	}
+	logger := opts.Logger
+	if logger == nil {
+		logger = log.New(io.Discard)
+	}
+	alphabet := Latin
+	if ap, ok := provider.(AlphabetProvider); ok {
+		alphabet = ap.Alphabet().orDefault()
+	}
+	return &Game{
+		provider:   provider,
+		alphabet:   alphabet,
+		state:      Loading,
+		keys:       map[rune]LetterState{},
+		rows:       opts.Rows,
+		length:     length,
+		hardMode:   opts.HardMode,
+		hintBudget: opts.Hints,
+		checker:    opts.Checker,
+		debug:      opts.Debug,
+		log:        logger,
+	}, nil
+}
+
+// Init initializes the answer provider. It may perform I/O, so it should not be called from a UI loop.
+// It returns an error if the provider cannot pick an answer before ctx is done or picks one that does not fit the grid.
+func (g *Game) Init(ctx context.Context) error {
+	g.log.Debug("Initializing answer provider")
+	if err := g.provider.Init(ctx); err != nil {
+		return fmt.Errorf("picking the answer: %w", err)
+	}
+	if n := len([]rune(g.provider.Answer())); n != g.length {
+		return fmt.Errorf("%w: the answer has %d letters, the grid has %d columns", ErrWordLength, n, g.length)
+	}
+	g.log.Debug("Answer provider initialized")
+	return nil
+}
+
+// Start starts the game with the answer picked by the provider
+func (g *Game) Start() {
+	g.log.Debug("== Starting Game ==")
+	g.setAnswer(g.provider.Answer())
+	if g.debug {
+		g.log.Debug("Answer", "answer", string(g.answer))
+	}
+	g.state = Playing
+}
+
+// setAnswer sets the answer and its folded form
+func (g *Game) setAnswer(answer string) {
+	g.answer = []rune(answer)
+	g.folded = []rune(g.alphabet.Fold(answer))
+}
+
+func (g *Game) State() State {
+	return g.state
+}
+
+func (g *Game) IsWon() bool {
+	return g.state == Won
+}
+
+func (g *Game) IsLost() bool {
+	return g.state == Lost
+}
+
+func (g *Game) InProgress() bool {
+	return g.state == Playing
+}
+
+func (g *Game) IsFinished() bool {
+	return g.state == Won || g.state == Lost
+}
+
+// Answer returns the answer of the game
+func (g *Game) Answer() string {
+	return string(g.answer)
+}
+
+// Rows returns the number of attempts
+func (g *Game) Rows() int {
+	return g.rows
+}
+
+// WordLength returns the number of letters per word
+func (g *Game) WordLength() int {
+	return g.length
+}
+
+// Alphabet returns the alphabet the game is played with
+func (g *Game) Alphabet() Alphabet {
+	return g.alphabet
+}
+
+func (g *Game) HardMode() bool {
+	return g.hardMode
+}
+
+// Guesses returns the submitted guesses with their feedback
+func (g *Game) Guesses() []Feedback {
+	return g.guesses
+}
+
+// Words returns the submitted guesses as strings
+func (g *Game) Words() []string {
+	words := make([]string, len(g.guesses))
+	for i, fb := range g.guesses {
+		words[i] = fb.Word()
+	}
+	return words
+}
+
+// KeyState returns the best state revealed so far for a letter or any of its variants,
+// NotChecked if it has not been guessed
+func (g *Game) KeyState(r rune) LetterState {
+	if state, ok := g.keys[g.alphabet.FoldRune(r)]; ok {
+		return state
+	}
+	return NotChecked
+}
+
+// Provider returns the answer provider of the game
+func (g *Game) Provider() AnswerProvider {
+	return g.provider
+}
+
+// PuzzleNumber returns the puzzle number if the answer provider produces numbered puzzles
+func (g *Game) PuzzleNumber() (int, bool) {
+	if np, ok := g.provider.(NumberedProvider); ok {
+		return np.PuzzleNumber(), true
+	}
+	return 0, false
+}
+
+// Mode returns the game mode as recorded in stats and save files
+func (g *Game) Mode() string {
+	if _, ok := g.PuzzleNumber(); ok {
+		return ModeDaily
+	}
+	return ModeRandom
+}
+
+// Difficulty returns the difficulty of the answer, normal if the answer provider has no difficulty
+func (g *Game) Difficulty() string {
+	if dp, ok := g.provider.(DifficultyProvider); ok {
+		return dp.Difficulty()
+	}
+	return DifficultyNormal
+}
+
+// CanToggleHardMode reports whether hard mode can be switched, which is only allowed before the first guess
+func (g *Game) CanToggleHardMode() bool {
+	return g.InProgress() && len(g.guesses) == 0
+}
+
+// ToggleHardMode switches hard mode on or off if no guess has been submitted yet
+func (g *Game) ToggleHardMode() bool {
+	if !g.CanToggleHardMode() {
+		return false
+	}
+	g.hardMode = !g.hardMode
+	g.log.Info("Hard mode toggled", "hardMode", g.hardMode)
+	return true
+}
+
+// Validate checks that word can be submitted as the next guess and returns an error if it cannot.
+// It may perform I/O such as validating the word with the answer provider,
+// so it should be called in a separate goroutine to avoid blocking a UI.
+// An error that is not one of the rules, such as a provider that could not be reached, means the word is unchecked.
+func (g *Game) Validate(ctx context.Context, word string) error {
+	if !g.InProgress() {
+		return fmt.Errorf("cannot submit: %w", ErrNotPlaying)
+	}
+	if len([]rune(word)) != g.length {
+		g.log.Info("Row is not full, cannot submit")
+		return fmt.Errorf("cannot submit: %w", ErrRowNotFull)
+	}
+	valid, err := g.valid(ctx, word)
+	if err != nil {
+		g.log.Error("Word could not be checked", "word", word, "err", err)
+		return fmt.Errorf("cannot submit: checking %q: %w", word, err)
+	}
+	if !valid {
+		g.log.Info("Invalid word submitted", "word", word)
+		return fmt.Errorf("cannot submit: %w", ErrInvalidWord)
+	}
+	if g.hardMode {
+		if err := g.checkHardMode([]rune(g.alphabet.Fold(word))); err != nil {
+			g.log.Info("Guess does not use revealed hints", "word", word, "reason", err)
+			return fmt.Errorf("cannot submit: %w", err)
+		}
+	}
+	return nil
+}
+
+// valid checks a word with the checker of the game, or with the answer provider if there is none.
+// The answer is always valid, even when the checker does not know it.
+func (g *Game) valid(ctx context.Context, word string) (bool, error) {
+	if g.checker == nil {
+		return g.provider.Valid(ctx, word)
+	}
+	if g.alphabet.Fold(word) == string(g.folded) {
+		return true, nil
+	}
+	return g.checker.Valid(ctx, word)
+}
+
+// checkHardMode checks that the folded guess keeps every green letter of the previous guesses in place
+// and contains every yellow letter, as many times as it was revealed in a single guess
+func (g *Game) checkHardMode(guess []rune) error {
+	counts := map[rune]int{}
+	for _, r := range guess {
+		counts[r]++
+	}
+	for _, prev := range g.guesses {
+		for i, l := range prev {
+			if r := g.alphabet.FoldRune(l.Rune); l.State == Matched && guess[i] != r {
+				return HardModeError{Err: ErrHardModeGreen, Letter: r, Position: i + 1}
+			}
+		}
+		required := map[rune]int{}
+		for _, l := range prev {
+			if l.State == Matched || l.State == Exists {
+				required[g.alphabet.FoldRune(l.Rune)]++
+			}
+		}
+		for _, l := range prev {
+			if r := g.alphabet.FoldRune(l.Rune); l.State == Exists && counts[r] < required[r] {
+				return HardModeError{Err: ErrHardModeYellow, Letter: r}
+			}
+		}
+	}
+	return nil
+}
+
+// Guess validates and submits a guess, returning its feedback.
+// The game is won when the guess matches the answer and lost when there are no attempts left.
+func (g *Game) Guess(ctx context.Context, word string) (Feedback, error) {
+	if err := g.Validate(ctx, word); err != nil {
+		return nil, err
+	}
+	return g.Apply(word)
+}
+
+// Apply submits a guess that Validate has accepted, without checking it with the answer provider again,
+// so it performs no I/O and can be called from a UI loop. It returns an error if the game ended or the word
+// does not fit the grid.
+func (g *Game) Apply(word string) (Feedback, error) {
+	if !g.InProgress() {
+		return nil, fmt.Errorf("cannot submit: %w", ErrNotPlaying)
+	}
+	if len([]rune(word)) != g.length {
+		return nil, fmt.Errorf("cannot submit: %w", ErrRowNotFull)
+	}
+	fb := g.score([]rune(word))
+	g.guesses = append(g.guesses, fb)
+	for _, l := range fb {
+		g.updateKey(l.Rune, l.State)
+	}
+	if fb.Solved() {
+		g.log.Info("Match found")
+		g.state = Won // mark the game as won
+		g.log.Debug("Marking game as finished.", "reason", "win")
+	} else if len(g.guesses) == g.rows {
+		g.state = Lost // mark the game as lost if there are no more rows
+		g.log.Debug("Marking game as finished.", "reason", "no more rows")
+	} else {
+		g.log.Info("Moving to next row")
+	}
+	return fb, nil
+}
+
+// score compares the folded guess with the folded answer and returns the feedback for each letter.
+// Matched letters take their form in the answer, the other letters are kept as typed apart from final forms.
+func (g *Game) score(guess []rune) Feedback {
+	states := Score(g.alphabet.Fold(string(guess)), string(g.folded))
+	fb := make(Feedback, len(guess))
+	for i, r := range []rune(g.alphabet.Display(string(guess))) {
+		if states[i] == Matched {
+			r = g.answer[i]
+		}
+		fb[i] = Letter{Rune: r, State: states[i]}
+	}
+	g.log.Debug("Scored guess", "word", string(guess), "feedback", states)
+	return fb
+}
+
+// updateKey records the state revealed for a letter, keeping the best one seen so far
+func (g *Game) updateKey(r rune, state LetterState) {
+	r = g.alphabet.FoldRune(r)
+	if current, ok := g.keys[r]; !ok || state < current {
+		g.log.Debug("Keyboard Update", "letter", string(r), "to", state, "from", current)
+		g.keys[r] = state
+	}
+}
+
+// Reset clears the guesses and starts the game again with the same answer.
+// The hints used are kept, so restarting does not give back the hint budget.
+func (g *Game) Reset() {
+	g.guesses = nil
+	clear(g.keys)
+	g.state = Playing
+}
+
+// Snapshot is the state of a game in progress as stored in a save file.
+// The answer is stored as an id understood by the answer provider rather than the word itself.
+type Snapshot struct {
+	Guesses  []string `json:"guesses"`
+	AnswerID string   `json:"answerId"`
+	Mode     string   `json:"mode"`
+	HardMode bool     `json:"hardMode"`
+	Hints    []Hint   `json:"hints,omitempty"`
+	Language string   `json:"language,omitempty"` // language of the alphabet, empty for English
+}
+
+// Snapshot returns the game state to be saved, or false if the answer provider cannot restore its answer
+func (g *Game) Snapshot() (Snapshot, bool) {
+	rp, ok := g.provider.(ResumableProvider)
+	if !ok {
+		return Snapshot{}, false
+	}
+	return Snapshot{
+		Guesses:  g.Words(),
+		AnswerID: rp.AnswerID(),
+		Mode:     g.Mode(),
+		HardMode: g.hardMode,
+		Hints:    slices.Clone(g.hints),
+		Language: g.alphabet.Language,
+	}, true
+}
+
+// CanRestore reports whether a snapshot fits this game's size, mode, language and answer provider
+func (g *Game) CanRestore(s Snapshot) bool {
+	if _, ok := g.provider.(ResumableProvider); !ok || s.Mode != g.Mode() || s.AnswerID == "" {
+		return false
+	}
+	if cmp.Or(s.Language, Latin.Language) != g.alphabet.Language {
+		return false
+	}
+	if len(s.Guesses) >= g.rows {
+		return false
+	}
+	return !slices.ContainsFunc(s.Guesses, func(w string) bool { return len([]rune(w)) != g.length })
+}
+
+// Restore replaces the current game with a saved one and restores its answer in the answer provider.
+// The saved guesses are replayed without validation, since they were valid when they were submitted.
+func (g *Game) Restore(s Snapshot) error {
+	rp, ok := g.provider.(ResumableProvider)
+	if !ok || !g.CanRestore(s) {
+		return ErrNotResumable
+	}
+	if err := rp.RestoreAnswer(s.AnswerID); err != nil {
+		return fmt.Errorf("%w: %w", ErrNotResumable, err)
+	}
+	g.setAnswer(g.provider.Answer())
+	g.Reset()
+	g.hardMode = s.HardMode
+	g.hints = slices.Clone(s.Hints)
+	for _, w := range s.Guesses {
+		fb := g.score([]rune(w))
+		g.guesses = append(g.guesses, fb)
+		for _, l := range fb {
+			g.updateKey(l.Rune, l.State)
+		}
+		// on a multi-board game the guesses made after a board was solved do not apply to it
+		if fb.Solved() {
+			g.state = Won
+			break
+		}
+	}
+	g.log.Info("Restored saved game", "guesses", len(g.guesses))
+	return nil
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestHardMode(t *testing.T) {
+	latin := []string{"crane", "slate", "trace", "pious", "react", "brace", "blade"}
+	repeated := []string{"geese", "emcee", "there", "eerie"}
+	greek := []string{"λόγος", "νόμος", "λόγοι", "άλογο", "λόγια"}
+	tests := []struct {
+		name     string
+		alphabet Alphabet
+		answers  []string // the answer first
+		guesses  []string
+		word     string
+		err      error
+		msg      string
+	}{
+		{"green kept", Latin, latin, []string{"slate"}, "trace", nil, ""},
+		{"green moved", Latin, latin, []string{"slate"}, "pious", ErrHardModeGreen, "must use A in position 3"},
+		{"yellow used", Latin, latin, []string{"react"}, "brace", nil, ""},
+		{"yellow missing", Latin, latin, []string{"react"}, "blade", ErrHardModeYellow, "must use R"},
+		{"every guess counts", Latin, latin, []string{"react", "brace"}, "react", ErrHardModeGreen, "must use R in position 2"},
+		{"repeated letters used", Latin, repeated, []string{"emcee"}, "eerie", nil, ""},
+		{"repeated letter used once", Latin, repeated, []string{"emcee"}, "there", ErrHardModeYellow, "must use E"},
+		{"greek without accents", Greek, greek, []string{"νόμος"}, "λογοσ", nil, ""},
+		{"greek final sigma moved", Greek, greek, []string{"νόμος"}, "λόγοι", ErrHardModeGreen, "must use Σ in position 5"},
+		{"greek yellow with accents", Greek, greek, []string{"άλογο"}, "λογος", nil, ""},
+		{"greek repeated letter used once", Greek, greek, []string{"άλογο"}, "λόγια", ErrHardModeYellow, "must use Ο"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t, tt.alphabet, tt.answers, Options{Rows: 6, HardMode: true}, tt.guesses...)
+			err := g.Validate(context.Background(), tt.word)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Validate(%q) = %v, want %v", tt.word, err, tt.err)
+			}
+			var herr HardModeError
+			if errors.As(err, &herr) && herr.Error() != tt.msg {
+				t.Errorf("Validate(%q) = %q, want %q", tt.word, herr.Error(), tt.msg)
+			}
+		})
+	}
+}
+
+func TestHardModeOff(t *testing.T) {
+	g := newTestGame(t, Latin, []string{"crane", "slate", "pious"}, Options{Rows: 6}, "slate")
+	if err := g.Validate(context.Background(), "pious"); err != nil {
+		t.Errorf("Validate(%q) = %v, want nil", "pious", err)
+	}
+}
@@ -0,0 +1,167 @@
+// hint.go gives hints about the answer, within a limited budget per game
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"unicode"
+)
+
+var (
+	ErrNoHintsLeft = errors.New("no hints left")
+	ErrNoHint      = errors.New("no hint available")
+)
+
+// HintKind is the kind of help given by a hint
+type HintKind int
+
+const (
+	HintLetter HintKind = iota // reveals a letter of the answer in its position
+	HintCount                  // tells how many answers still fit the guesses and hints so far
+	HintAbsent                 // names a letter that is not in the answer
+)
+
+// Hint is a hint given during a game
+type Hint struct {
+	Kind     HintKind `json:"kind"`
+	Letter   rune     `json:"letter,omitempty"`   // revealed or absent letter
+	Position int      `json:"position,omitempty"` // position of the revealed letter, starting at 1
+	Count    int      `json:"count,omitempty"`    // number of answers left
+	Board    int      `json:"board,omitempty"`    // board the hint is about in a multi-board game, starting at 0
+}
+
+func (h Hint) String() string {
+	switch h.Kind {
+	case HintLetter:
+		return fmt.Sprintf("%c is in position %d", unicode.ToUpper(h.Letter), h.Position)
+	case HintCount:
+		if h.Count == 1 {
+			return "1 possible answer left"
+		}
+		return fmt.Sprintf("%d possible answers left", h.Count)
+	case HintAbsent:
+		return fmt.Sprintf("%c is not in the word", unicode.ToUpper(h.Letter))
+	}
+	return ""
+}
+
+// ListedProvider is implemented by answer providers that can list every possible answer
+type ListedProvider interface {
+	Answers() []string
+}
+
+// Hints returns the hints given so far
+func (g *Game) Hints() []Hint {
+	return g.hints
+}
+
+// HintsLeft returns the number of hints that can still be used in this game
+func (g *Game) HintsLeft() int {
+	return max(0, g.hintBudget-len(g.hints))
+}
+
+// Revealed returns the letter revealed by a hint at position i, starting at 0, if any
+func (g *Game) Revealed(i int) (rune, bool) {
+	for _, h := range g.hints {
+		if h.Kind == HintLetter && h.Position == i+1 {
+			return h.Letter, true
+		}
+	}
+	return 0, false
+}
+
+// Hint uses one hint of the budget and returns it.
+// It returns ErrNoHint without using the budget if there is nothing left to tell for that kind of hint.
+func (g *Game) Hint(kind HintKind) (Hint, error) {
+	if !g.InProgress() {
+		return Hint{}, ErrNotPlaying
+	}
+	if g.HintsLeft() == 0 {
+		return Hint{}, ErrNoHintsLeft
+	}
+	var h Hint
+	var err error
+	switch kind {
+	case HintLetter:
+		h, err = g.letterHint()
+	case HintCount:
+		h, err = g.countHint()
+	case HintAbsent:
+		h, err = g.absentHint()
+	default:
+		err = fmt.Errorf("%w: unknown kind %d", ErrNoHint, kind)
+	}
+	if err != nil {
+		return Hint{}, err
+	}
+	g.hints = append(g.hints, h)
+	g.log.Info("Hint used", "kind", kind, "left", g.HintsLeft())
+	return h, nil
+}
+
+// letterHint reveals the first letter of the answer that has not been found yet
+func (g *Game) letterHint() (Hint, error) {
+	for i, r := range g.answer {
+		if _, ok := g.Revealed(i); ok {
+			continue
+		}
+		if slices.ContainsFunc(g.guesses, func(fb Feedback) bool { return fb[i].State == Matched }) {
+			continue
+		}
+		return Hint{Kind: HintLetter, Letter: r, Position: i + 1}, nil
+	}
+	return Hint{}, fmt.Errorf("%w: every letter has been found", ErrNoHint)
+}
+
+// countHint counts the answers that fit the guesses and the hints so far
+func (g *Game) countHint() (Hint, error) {
+	lp, ok := g.provider.(ListedProvider)
+	if !ok {
+		return Hint{}, fmt.Errorf("%w: the answer list is unknown", ErrNoHint)
+	}
+	count := 0
+	for _, w := range lp.Answers() {
+		if g.fits([]rune(g.alphabet.Fold(w))) {
+			count++
+		}
+	}
+	return Hint{Kind: HintCount, Count: count}, nil
+}
+
+// fits reports whether the folded word could be the answer given the guesses and hints so far
+func (g *Game) fits(word []rune) bool {
+	for _, fb := range g.guesses {
+		if !slices.Equal(Score(g.alphabet.Fold(fb.Word()), string(word)), fb.States()) {
+			return false
+		}
+	}
+	for _, h := range g.hints {
+		switch h.Kind {
+		case HintLetter:
+			if word[h.Position-1] != g.alphabet.FoldRune(h.Letter) {
+				return false
+			}
+		case HintAbsent:
+			if slices.Contains(word, h.Letter) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// absentHint names the most common letter that is not in the answer and has not been guessed or named yet
+func (g *Game) absentHint() (Hint, error) {
+	// letters are tried most common first so the hint rules out more words
+	for _, r := range g.alphabet.Letters {
+		if slices.Contains(g.folded, r) || g.KeyState(r) != NotChecked {
+			continue
+		}
+		if slices.Contains(g.hints, Hint{Kind: HintAbsent, Letter: r}) {
+			continue
+		}
+		return Hint{Kind: HintAbsent, Letter: r}, nil
+	}
+	return Hint{}, fmt.Errorf("%w: every absent letter has been found", ErrNoHint)
+}
@@ -0,0 +1,225 @@
+package engine
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestGame starts a game whose answer is the first word of answers, with the guesses already submitted
+func newTestGame(t *testing.T, a Alphabet, answers []string, opts Options, guesses ...string) *Game {
+	t.Helper()
+	p, err := NewDictionaryProvider(answers, nil, a)
+	if err != nil {
+		t.Fatalf("NewDictionaryProvider: %v", err)
+	}
+	p.answer = answers[0]
+	g, err := NewGame(p, opts)
+	if err != nil {
+		t.Fatalf("NewGame: %v", err)
+	}
+	g.Start()
+	for _, w := range guesses {
+		if _, err := g.Apply(w); err != nil {
+			t.Fatalf("Apply(%q): %v", w, err)
+		}
+	}
+	return g
+}
+
+func TestHint(t *testing.T) {
+	answers := []string{"crane", "crate", "trace", "slate", "pious"}
+	tests := []struct {
+		name    string
+		guesses []string
+		budget  int
+		kinds   []HintKind // hints used in order, only the last one is checked
+		want    Hint
+		err     error
+	}{
+		{"letter", nil, 3, []HintKind{HintLetter}, Hint{Kind: HintLetter, Letter: 'c', Position: 1}, nil},
+		{"letter after a revealed letter", nil, 3, []HintKind{HintLetter, HintLetter}, Hint{Kind: HintLetter, Letter: 'r', Position: 2}, nil},
+		{"letter not found by the guesses", []string{"crate"}, 3, []HintKind{HintLetter}, Hint{Kind: HintLetter, Letter: 'n', Position: 4}, nil},
+		{"every letter has been found", []string{"crate"}, 3, []HintKind{HintLetter, HintLetter}, Hint{}, ErrNoHint},
+		{"count", nil, 3, []HintKind{HintCount}, Hint{Kind: HintCount, Count: 5}, nil},
+		{"count after a guess", []string{"slate"}, 3, []HintKind{HintCount}, Hint{Kind: HintCount, Count: 1}, nil},
+		{"count after a letter", nil, 3, []HintKind{HintLetter, HintCount}, Hint{Kind: HintCount, Count: 2}, nil},
+		{"absent", nil, 3, []HintKind{HintAbsent}, Hint{Kind: HintAbsent, Letter: 't'}, nil},
+		{"absent skips guessed letters", []string{"slate"}, 3, []HintKind{HintAbsent}, Hint{Kind: HintAbsent, Letter: 'o'}, nil},
+		{"absent skips named letters", nil, 3, []HintKind{HintAbsent, HintAbsent}, Hint{Kind: HintAbsent, Letter: 'o'}, nil},
+		{"budget runs out", nil, 2, []HintKind{HintLetter, HintAbsent, HintCount}, Hint{}, ErrNoHintsLeft},
+		{"no budget", nil, 0, []HintKind{HintLetter}, Hint{}, ErrNoHintsLeft},
+		{"unknown kind", nil, 3, []HintKind{HintKind(9)}, Hint{}, ErrNoHint},
+		{"game over", []string{"crane"}, 3, []HintKind{HintLetter}, Hint{}, ErrNotPlaying},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t, Latin, answers, Options{Rows: 6, Hints: tt.budget}, tt.guesses...)
+			last := len(tt.kinds) - 1
+			for _, kind := range tt.kinds[:last] {
+				if _, err := g.Hint(kind); err != nil {
+					t.Fatalf("Hint(%d): %v", kind, err)
+				}
+			}
+			left := g.HintsLeft()
+			got, err := g.Hint(tt.kinds[last])
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Hint(%d) = %v, want %v", tt.kinds[last], err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Hint(%d) = %+v, want %+v", tt.kinds[last], got, tt.want)
+			}
+			if err == nil {
+				left--
+			}
+			if g.HintsLeft() != left {
+				t.Errorf("HintsLeft() = %d, want %d", g.HintsLeft(), left)
+			}
+		})
+	}
+}
+
+func TestCountHintWithoutList(t *testing.T) {
+	g, err := NewGame(StaticProvider{Word: "crane"}, Options{Rows: 6, Hints: 3})
+	if err != nil {
+		t.Fatalf("NewGame: %v", err)
+	}
+	g.Start()
+	if _, err := g.Hint(HintCount); !errors.Is(err, ErrNoHint) {
+		t.Errorf("Hint(HintCount) = %v, want %v", err, ErrNoHint)
+	}
+	if g.HintsLeft() != 3 {
+		t.Errorf("HintsLeft() = %d, want 3", g.HintsLeft())
+	}
+}
+
+func TestFits(t *testing.T) {
+	letter := Hint{Kind: HintLetter, Letter: 'c', Position: 1}
+	absent := Hint{Kind: HintAbsent, Letter: 'o'}
+	tests := []struct {
+		name    string
+		guesses []string
+		hints   []Hint
+		word    string
+		want    bool
+	}{
+		{"nothing known", nil, nil, "pious", true},
+		{"same feedback", []string{"slate"}, nil, "brane", true},
+		{"other feedback", []string{"slate"}, nil, "trace", false},
+		{"revealed letter", nil, []Hint{letter}, "crone", true},
+		{"revealed letter missing", nil, []Hint{letter}, "brane", false},
+		{"absent letter", nil, []Hint{absent}, "crane", true},
+		{"absent letter used", nil, []Hint{absent}, "crone", false},
+		{"guesses and hints", []string{"slate"}, []Hint{letter, absent}, "crane", true},
+		{"guesses but not hints", []string{"slate"}, []Hint{letter, absent}, "brane", false},
+		{"hints but not guesses", []string{"slate"}, []Hint{letter, absent}, "cruse", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := newTestGame(t, Latin, []string{"crane"}, Options{Rows: 6, Hints: 3}, tt.guesses...)
+			g.hints = tt.hints
+			if got := g.fits([]rune(tt.word)); got != tt.want {
+				t.Errorf("fits(%q) = %v, want %v", tt.word, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResetKeepsHints(t *testing.T) {
+	g := newTestGame(t, Latin, []string{"crane", "slate"}, Options{Rows: 6, Hints: 1})
+	h, err := g.Hint(HintLetter)
+	if err != nil {
+		t.Fatalf("Hint: %v", err)
+	}
+	if _, err := g.Apply("slate"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	g.Reset()
+	if len(g.Guesses()) != 0 {
+		t.Errorf("Guesses() = %v after Reset, want none", g.Words())
+	}
+	if hints := g.Hints(); len(hints) != 1 || hints[0] != h {
+		t.Errorf("Hints() = %v after Reset, want [%v]", hints, h)
+	}
+	if _, err := g.Hint(HintLetter); !errors.Is(err, ErrNoHintsLeft) {
+		t.Errorf("Hint() after Reset = %v, want %v", err, ErrNoHintsLeft)
+	}
+}
+
+// newTestMultiGame starts a game with one board per answer, with the guesses already submitted
+func newTestMultiGame(t *testing.T, answers []string, opts Options, guesses ...string) *MultiGame {
+	t.Helper()
+	var providers []AnswerProvider
+	for _, a := range answers {
+		providers = append(providers, StaticProvider{Word: a})
+	}
+	m, err := NewMultiGame(providers, opts)
+	if err != nil {
+		t.Fatalf("NewMultiGame: %v", err)
+	}
+	m.Start()
+	for _, w := range guesses {
+		if _, err := m.Apply(w); err != nil {
+			t.Fatalf("Apply(%q): %v", w, err)
+		}
+	}
+	return m
+}
+
+func TestMultiGameHint(t *testing.T) {
+	tests := []struct {
+		name    string
+		guesses []string
+		budget  int
+		kinds   []HintKind // hints used in order, only the last one is checked
+		want    Hint
+		err     error
+	}{
+		{"first board", nil, 3, []HintKind{HintLetter}, Hint{Kind: HintLetter, Letter: 'c', Position: 1}, nil},
+		{"first board not solved", []string{"crane"}, 3, []HintKind{HintLetter}, Hint{Kind: HintLetter, Letter: 's', Position: 1, Board: 1}, nil},
+		{"budget shared by the boards", nil, 2, []HintKind{HintLetter, HintAbsent, HintLetter}, Hint{}, ErrNoHintsLeft},
+		{"game over", []string{"crane", "slate"}, 3, []HintKind{HintLetter}, Hint{}, ErrNotPlaying},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMultiGame(t, []string{"crane", "slate"}, Options{Rows: 6, Hints: tt.budget}, tt.guesses...)
+			last := len(tt.kinds) - 1
+			for _, kind := range tt.kinds[:last] {
+				if _, err := m.Hint(kind); err != nil {
+					t.Fatalf("Hint(%d): %v", kind, err)
+				}
+			}
+			got, err := m.Hint(tt.kinds[last])
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("Hint(%d) = %v, want %v", tt.kinds[last], err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("Hint(%d) = %+v, want %+v", tt.kinds[last], got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiGameHintBudget(t *testing.T) {
+	m := newTestMultiGame(t, []string{"crane", "slate"}, Options{Rows: 6, Hints: 2})
+	if _, err := m.Hint(HintLetter); err != nil {
+		t.Fatalf("Hint on the first board: %v", err)
+	}
+	if _, err := m.Apply("crane"); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	h, err := m.Hint(HintLetter)
+	if err != nil || h.Board != 1 {
+		t.Fatalf("Hint on the second board = %+v, %v, want board 1", h, err)
+	}
+	// each board has a hint of its own left, but the budget is for the whole game
+	if m.HintsLeft() != 0 {
+		t.Errorf("HintsLeft() = %d, want 0", m.HintsLeft())
+	}
+	if _, err := m.Hint(HintLetter); !errors.Is(err, ErrNoHintsLeft) {
+		t.Errorf("Hint() = %v, want %v", err, ErrNoHintsLeft)
+	}
+	m.Reset()
+	if m.HintsLeft() != 0 {
+		t.Errorf("HintsLeft() = %d after Reset, want 0", m.HintsLeft())
+	}
+}
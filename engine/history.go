@@ -0,0 +1,78 @@
+// history.go keeps random answers from repeating until every answer has been played
+package engine
+
+import (
+	"slices"
+	"sync"
+)
+
+// AnswerHistory holds the answers already played, so a DictionaryProvider does not pick an answer again until every
+// answer it can pick has been played. A new round then starts, without the last answer played so it cannot come
+// twice in a row. Answers added to the list later are simply not played yet.
+// A history can be shared by the providers of several boards.
+type AnswerHistory struct {
+	mu     sync.Mutex
+	played []string // answers in the order they were played
+}
+
+// NewAnswerHistory creates a history with the answers played so far, oldest first
+func NewAnswerHistory(played []string) *AnswerHistory {
+	return &AnswerHistory{played: slices.Clone(played)}
+}
+
+// Add records answers as played
+func (h *AnswerHistory) Add(answers ...string) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, a := range answers {
+		if !slices.Contains(h.played, a) {
+			h.played = append(h.played, a)
+		}
+	}
+}
+
+// Played returns the answers played in the current round, oldest first
+func (h *AnswerHistory) Played() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.played)
+}
+
+// unplayed returns the indices of the answers of the pool that have not been played, with their weights.
+// When every answer of the pool has been played, they are forgotten but the last one and a new round starts.
+func (h *AnswerHistory) unplayed(answers []string, pool []int, weights []float64) ([]int, []float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	left, leftWeights := h.filter(answers, pool, weights)
+	if len(left) > 0 {
+		return left, leftWeights
+	}
+	last := ""
+	if n := len(h.played); n > 0 {
+		last = h.played[n-1]
+	}
+	h.played = slices.DeleteFunc(h.played, func(w string) bool {
+		return w != last && slices.ContainsFunc(pool, func(i int) bool { return answers[i] == w })
+	})
+	if left, leftWeights = h.filter(answers, pool, weights); len(left) > 0 {
+		return left, leftWeights
+	}
+	// a pool of a single answer
+	return pool, weights
+}
+
+// filter returns the indices and weights of the answers of the pool that have not been played
+func (h *AnswerHistory) filter(answers []string, pool []int, weights []float64) ([]int, []float64) {
+	var left []int
+	var leftWeights []float64
+	for i, a := range pool {
+		if slices.Contains(h.played, answers[a]) {
+			continue
+		}
+		left = append(left, a)
+		if weights != nil {
+			leftWeights = append(leftWeights, weights[i])
+		}
+	}
+	return left, leftWeights
+}
@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestAnswerHistoryUnplayed(t *testing.T) {
+	answers := []string{"crane", "slate", "pious", "tipsy"}
+	tests := []struct {
+		name        string
+		played      []string
+		pool        []int
+		weights     []float64
+		want        []string
+		wantWeights []float64
+		wantPlayed  []string // the history after the pick
+	}{
+		{"nothing played", nil, []int{0, 1, 2, 3}, nil, []string{"crane", "slate", "pious", "tipsy"}, nil, nil},
+		{"played answers are left out", []string{"slate", "tipsy"}, []int{0, 1, 2, 3}, nil, []string{"crane", "pious"}, nil, []string{"slate", "tipsy"}},
+		{"weights follow their answers", []string{"slate"}, []int{0, 1, 2}, []float64{3, 2, 1}, []string{"crane", "pious"}, []float64{3, 1}, []string{"slate"}},
+		{"answers outside the pool are kept", []string{"pious"}, []int{0, 1}, nil, []string{"crane", "slate"}, nil, []string{"pious"}},
+		{"new round without the last answer", []string{"tipsy", "crane", "slate"}, []int{0, 1}, []float64{2, 1}, []string{"crane"}, []float64{2}, []string{"tipsy", "slate"}},
+		{"new round after an answer outside the pool", []string{"crane", "slate", "tipsy"}, []int{0, 1}, nil, []string{"crane", "slate"}, nil, []string{"tipsy"}},
+		{"single answer pool", []string{"pious"}, []int{2}, []float64{1}, []string{"pious"}, []float64{1}, []string{"pious"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := NewAnswerHistory(tt.played)
+			pool, weights := h.unplayed(answers, tt.pool, tt.weights)
+			var got []string
+			for _, i := range pool {
+				got = append(got, answers[i])
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("unplayed() = %v, want %v", got, tt.want)
+			}
+			if !slices.Equal(weights, tt.wantWeights) {
+				t.Errorf("unplayed() weights = %v, want %v", weights, tt.wantWeights)
+			}
+			if played := h.Played(); !slices.Equal(played, tt.wantPlayed) {
+				t.Errorf("Played() = %v, want %v", played, tt.wantPlayed)
+			}
+		})
+	}
+}
+
+func TestAnswerHistoryAdd(t *testing.T) {
+	h := NewAnswerHistory([]string{"crane"})
+	h.Add("slate", "crane", "pious", "slate")
+	if want := []string{"crane", "slate", "pious"}; !slices.Equal(h.Played(), want) {
+		t.Errorf("Played() = %v, want %v", h.Played(), want)
+	}
+}
@@ -0,0 +1,416 @@
+// http.go gets the daily answer and checks guesses with a word service over HTTP, caching the responses on disk
+package engine
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/log"
+)
+
+var (
+	ErrWordService = errors.New("word service error")
+	ErrRateLimited = errors.New("rate limited by the word service")
+)
+
+// default limits of the requests to a word service
+const (
+	DefaultServiceTimeout  = 3 * time.Second        // timeout of a request
+	DefaultServiceInterval = 250 * time.Millisecond // minimum time between two requests
+	DefaultServiceBackoff  = 30 * time.Second       // time without requests after the service failed
+)
+
+// maxResponseSize is the largest response body read from a word service
+const maxResponseSize = 64 << 10
+
+// FallbackProvider is implemented by providers that fall back to a local word list when their source cannot be used
+type FallbackProvider interface {
+	// Fallback returns the reason the local word list is used, nil if the source answered
+	Fallback() error
+}
+
+// ServiceOptions configures a WordService
+type ServiceOptions struct {
+	URL       string        // base URL of the service
+	Timeout   time.Duration // timeout of a request, 0 for DefaultServiceTimeout
+	Interval  time.Duration // minimum time between two requests, 0 for DefaultServiceInterval
+	Backoff   time.Duration // time without requests after the service failed, 0 for DefaultServiceBackoff
+	CachePath string        // path of the cache file, empty to keep the responses in memory only
+	Client    *http.Client  // nil for http.DefaultClient
+	Logger    *log.Logger   // nil to disable logging
+}
+
+// WordService is a client of an HTTP word service, shared by the providers of every board. It has two endpoints:
+//
+//	GET <url>/answer?number=12&length=5&lang=en&board=0&boards=1  {"word": "crane"}
+//	GET <url>/valid?word=crane&lang=en                              {"valid": true}
+//
+// Answers and checked words are cached on disk, so puzzles and words seen once keep working offline.
+// Requests are spaced by the interval, and after a failure or a 429 response the service is not called again
+// until the backoff, or the Retry-After delay, has passed.
+type WordService struct {
+	url       string
+	timeout   time.Duration
+	interval  time.Duration
+	backoff   time.Duration
+	client    *http.Client
+	log       *log.Logger
+	cachePath string
+
+	mu           sync.Mutex
+	cache        serviceCache
+	next         time.Time // earliest time of the next request
+	blockedUntil time.Time // no request is made before this time after a failure
+	blockErr     error     // failure that blocks the requests
+}
+
+// serviceCache holds the responses of a word service
+type serviceCache struct {
+	Answers map[string]string `json:"answers"` // answers by puzzle, see answerKey
+	Words   map[string]bool   `json:"words"`   // checked words by language and folded word, valid or not
+}
+
+// NewWordService creates a client of the word service at opts.URL and loads its cache file, if any
+func NewWordService(opts ServiceOptions) (*WordService, error) {
+	u, err := url.Parse(opts.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: invalid URL %q", ErrWordService, opts.URL)
+	}
+	s := &WordService{
+		url:       strings.TrimSuffix(opts.URL, "/"),
+		timeout:   cmp.Or(opts.Timeout, DefaultServiceTimeout),
+		interval:  cmp.Or(opts.Interval, DefaultServiceInterval),
+		backoff:   cmp.Or(opts.Backoff, DefaultServiceBackoff),
+		client:    opts.Client,
+		log:       opts.Logger,
+		cachePath: opts.CachePath,
+		cache:     serviceCache{Answers: map[string]string{}, Words: map[string]bool{}},
+	}
+	if s.client == nil {
+		s.client = http.DefaultClient
+	}
+	if s.log == nil {
+		s.log = log.New(io.Discard)
+	}
+	if s.cachePath == "" {
+		return s, nil
+	}
+	data, err := os.ReadFile(s.cachePath)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(data, &s.cache); err != nil {
+		// a broken cache only costs requests, it is replaced on the next response
+		s.log.Error("Ignoring broken word service cache", "path", s.cachePath, "err", err)
+	}
+	if s.cache.Answers == nil {
+		s.cache.Answers = map[string]string{}
+	}
+	if s.cache.Words == nil {
+		s.cache.Words = map[string]bool{}
+	}
+	return s, nil
+}
+
+// answerKey identifies a puzzle in the cache and in the answer request
+func answerKey(number, length int, lang string, board, boards int) string {
+	return fmt.Sprintf("%s/%d/%d/%d/%d", lang, length, number, board, boards)
+}
+
+// answer returns the answer of a board of a daily puzzle, lowercased, from the cache or the service.
+// An answer without the length or the letters of the alphabet is an error and is not cached,
+// so the service is asked again for the next game.
+func (s *WordService) answer(ctx context.Context, number, length int, a Alphabet, board, boards int) (string, error) {
+	if word, ok := s.cachedAnswer(number, length, a, board, boards); ok {
+		return word, nil
+	}
+	var resp struct {
+		Word string `json:"word"`
+	}
+	query := url.Values{
+		"number": {strconv.Itoa(number)},
+		"length": {strconv.Itoa(length)},
+		"lang":   {a.Language},
+		"board":  {strconv.Itoa(board)},
+		"boards": {strconv.Itoa(boards)},
+	}
+	if err := s.get(ctx, "/answer", query, &resp); err != nil {
+		return "", err
+	}
+	word := strings.ToLower(resp.Word)
+	if !fitsAnswer(word, length, a) {
+		return "", fmt.Errorf("%w: invalid answer %q", ErrWordService, resp.Word)
+	}
+	s.mu.Lock()
+	s.cache.Answers[answerKey(number, length, a.Language, board, boards)] = word
+	s.mu.Unlock()
+	s.save()
+	return word, nil
+}
+
+// cachedAnswer returns the answer of a puzzle if it is in the cache and fits the word length and the alphabet
+func (s *WordService) cachedAnswer(number, length int, a Alphabet, board, boards int) (string, bool) {
+	s.mu.Lock()
+	word, ok := s.cache.Answers[answerKey(number, length, a.Language, board, boards)]
+	s.mu.Unlock()
+	word = strings.ToLower(word)
+	return word, ok && fitsAnswer(word, length, a)
+}
+
+// fitsAnswer reports whether a word from the service has the length and the letters of the local word list
+func fitsAnswer(word string, length int, a Alphabet) bool {
+	return utf8.RuneCountInString(word) == length && strings.IndexFunc(word, func(r rune) bool { return !a.Contains(r) }) < 0
+}
+
+// valid reports whether the service accepts a folded word, from the cache or the service
+func (s *WordService) valid(ctx context.Context, word, lang string) (bool, error) {
+	key := lang + "/" + word
+	s.mu.Lock()
+	valid, ok := s.cache.Words[key]
+	s.mu.Unlock()
+	if ok {
+		return valid, nil
+	}
+	var resp struct {
+		Valid bool `json:"valid"`
+	}
+	if err := s.get(ctx, "/valid", url.Values{"word": {word}, "lang": {lang}}, &resp); err != nil {
+		return false, err
+	}
+	s.mu.Lock()
+	s.cache.Words[key] = resp.Valid
+	s.mu.Unlock()
+	s.save()
+	return resp.Valid, nil
+}
+
+// get sends a request to the service and decodes its JSON response into v
+func (s *WordService) get(ctx context.Context, path string, query url.Values, v any) error {
+	if err := s.wait(ctx); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url+path+"?"+query.Encode(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return s.fail(fmt.Errorf("%w: %w", ErrWordService, err), s.backoff)
+	}
+	//nolint:errcheck
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return s.fail(ErrRateLimited, retryAfter(resp.Header.Get("Retry-After"), s.backoff))
+	case resp.StatusCode >= http.StatusInternalServerError:
+		return s.fail(fmt.Errorf("%w: %s", ErrWordService, resp.Status), s.backoff)
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("%w: %s", ErrWordService, resp.Status)
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxResponseSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: invalid response: %w", ErrWordService, err)
+	}
+	return nil
+}
+
+// wait waits until the next request is allowed. It fails at once while the service is blocked after a failure.
+func (s *WordService) wait(ctx context.Context) error {
+	s.mu.Lock()
+	now := time.Now()
+	if now.Before(s.blockedUntil) {
+		err := s.blockErr
+		s.mu.Unlock()
+		return err
+	}
+	delay := s.next.Sub(now)
+	s.next = now.Add(max(delay, 0) + s.interval)
+	s.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// fail blocks the requests for d after a failure and returns err
+func (s *WordService) fail(err error, d time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.blockedUntil = time.Now().Add(d)
+	s.blockErr = err
+	s.log.Warn("Word service failed", "err", err, "retry", d)
+	return err
+}
+
+// retryAfter returns the delay of a Retry-After header given in seconds, or def if there is none
+func retryAfter(header string, def time.Duration) time.Duration {
+	if n, err := strconv.Atoi(header); err == nil && n >= 0 {
+		return time.Duration(n) * time.Second
+	}
+	return def
+}
+
+// save writes the cache file. A failure is logged, the responses are still kept in memory.
+func (s *WordService) save() {
+	if s.cachePath == "" {
+		return
+	}
+	s.mu.Lock()
+	data, err := json.Marshal(s.cache)
+	s.mu.Unlock()
+	if err == nil {
+		err = os.MkdirAll(filepath.Dir(s.cachePath), 0o755)
+	}
+	if err == nil {
+		tmp := s.cachePath + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, s.cachePath)
+		}
+	}
+	if err != nil {
+		s.log.Error("Failed to save word service cache", "path", s.cachePath, "err", err)
+	}
+}
+
+// HTTPProvider is an implementation of AnswerProvider that gets the daily answer and checks guesses with a
+// word service. When the service cannot be reached, is rate limiting or fails, the answer and the checks come
+// from a local daily provider instead and Fallback reports why.
+type HTTPProvider struct {
+	service *WordService
+	local   *DailyProvider // answers and checks words when the service cannot be used
+	answer  string
+	number  int
+
+	mu       sync.Mutex
+	fallback error // reason the local word list is used, read by the UI while a guess is checked
+}
+
+// NewHTTPProvider creates a provider using a word service, with a local daily provider as fallback.
+// The puzzle number, board and word length are the ones of the local provider.
+func NewHTTPProvider(service *WordService, local *DailyProvider) *HTTPProvider {
+	return &HTTPProvider{service: service, local: local}
+}
+
+func (p *HTTPProvider) Init(ctx context.Context) error {
+	return p.setPuzzle(ctx, p.local.puzzleNumberAt(p.local.now()))
+}
+
+func (p *HTTPProvider) Answer() string {
+	return p.answer
+}
+
+// Valid checks the word with the service, or with the local word list if the service cannot be used.
+// The answer is always valid, even when it is not in the local word list.
+func (p *HTTPProvider) Valid(ctx context.Context, word string) (bool, error) {
+	a := p.Alphabet()
+	folded := a.Fold(word)
+	if folded == a.Fold(p.answer) {
+		return true, nil
+	}
+	valid, err := p.service.valid(ctx, folded, a.Language)
+	if err == nil {
+		return valid, nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return false, ctxErr
+	}
+	p.setFallback(err)
+	return p.local.Valid(ctx, word)
+}
+
+func (p *HTTPProvider) WordLength() int {
+	return p.local.WordLength()
+}
+
+// Alphabet returns the alphabet of the local word list
+func (p *HTTPProvider) Alphabet() Alphabet {
+	return p.local.Alphabet()
+}
+
+func (p *HTTPProvider) PuzzleNumber() int {
+	return p.number
+}
+
+// Fallback returns the reason the local word list is used, nil if the service answered
+func (p *HTTPProvider) Fallback() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.fallback
+}
+
+// setFallback records the reason the local word list is used
+func (p *HTTPProvider) setFallback(err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.fallback = err
+}
+
+// AnswerID identifies the answer by its puzzle number
+func (p *HTTPProvider) AnswerID() string {
+	return strconv.Itoa(p.number)
+}
+
+// RestoreAnswer sets the puzzle identified by id, as returned by AnswerID, from the cache.
+// It returns ErrUnknownAnswer for a puzzle that is not in the cache, since the local word list
+// may give it a different answer than the service did.
+func (p *HTTPProvider) RestoreAnswer(id string) error {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnknownAnswer, id)
+	}
+	word, ok := p.service.cachedAnswer(n, p.WordLength(), p.Alphabet(), p.local.board, p.local.boards)
+	if ok {
+		p.answer, p.number = word, n
+		p.setFallback(nil)
+		return nil
+	}
+	return fmt.Errorf("%w: puzzle #%d is not in the cache", ErrUnknownAnswer, n)
+}
+
+// setPuzzle sets the answer of puzzle n from the service, or from the local word list if the service cannot be used
+func (p *HTTPProvider) setPuzzle(ctx context.Context, n int) error {
+	word, err := p.service.answer(ctx, n, p.WordLength(), p.Alphabet(), p.local.board, p.local.boards)
+	if err == nil {
+		p.answer, p.number = word, n
+		p.setFallback(nil)
+		return nil
+	}
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	p.setFallback(err)
+	return p.useLocal(n)
+}
+
+// useLocal sets the answer of puzzle n from the local word list
+func (p *HTTPProvider) useLocal(n int) error {
+	if err := p.local.SetPuzzle(n); err != nil {
+		return err
+	}
+	p.answer, p.number = p.local.Answer(), n
+	return nil
+}
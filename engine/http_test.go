@@ -0,0 +1,280 @@
+package engine
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// testPuzzle is the puzzle number of the day the test providers are played on
+const testPuzzle = 3
+
+// newTestService starts a word service answering with handler and returns a client with its cache at cachePath,
+// and the number of requests the service has received
+func newTestService(t *testing.T, handler http.HandlerFunc, cachePath string) (*WordService, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		handler(w, r)
+	}))
+	t.Cleanup(srv.Close)
+	s, err := NewWordService(ServiceOptions{URL: srv.URL, Interval: time.Nanosecond, CachePath: cachePath})
+	if err != nil {
+		t.Fatalf("NewWordService: %v", err)
+	}
+	return s, &requests
+}
+
+// newTestHTTPProvider creates a provider using service, with a local word list as fallback
+func newTestHTTPProvider(t *testing.T, service *WordService) *HTTPProvider {
+	t.Helper()
+	dict, err := NewDictionaryProvider([]string{"crane", "slate", "pious"}, []string{"tipsy"}, Latin)
+	if err != nil {
+		t.Fatalf("NewDictionaryProvider: %v", err)
+	}
+	local := NewDailyProvider(dict, time.UTC)
+	local.now = func() time.Time { return dailyEpoch.AddDate(0, 0, testPuzzle-1) }
+	return NewHTTPProvider(service, local)
+}
+
+// localAnswer returns the answer of puzzle n from the local word list of p
+func localAnswer(t *testing.T, p *HTTPProvider, n int) string {
+	t.Helper()
+	if err := p.local.SetPuzzle(n); err != nil {
+		t.Fatalf("SetPuzzle(%d): %v", n, err)
+	}
+	return p.local.Answer()
+}
+
+// answerHandler answers every request for an answer with word and accepts every guess
+func answerHandler(word string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/answer":
+			_ = json.NewEncoder(w).Encode(map[string]string{"word": word})
+		case "/valid":
+			_ = json.NewEncoder(w).Encode(map[string]bool{"valid": true})
+		default:
+			http.NotFound(w, r)
+		}
+	}
+}
+
+func TestHTTPProviderCache(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	service, requests := newTestService(t, answerHandler("FLINT"), cachePath)
+	p := newTestHTTPProvider(t, service)
+	if err := p.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if got := p.Answer(); got != "flint" {
+		t.Errorf("Answer() = %q, want %q", got, "flint")
+	}
+	if err := p.Fallback(); err != nil {
+		t.Errorf("Fallback() = %v, want nil", err)
+	}
+	for range 2 {
+		if valid, err := p.Valid(context.Background(), "tipsy"); err != nil || !valid {
+			t.Errorf("Valid(tipsy) = %v, %v, want true, nil", valid, err)
+		}
+	}
+	if got := requests.Load(); got != 2 {
+		t.Errorf("service got %d requests, want 2: the second check must come from the cache", got)
+	}
+
+	// a new client reads the responses from the cache file
+	service, requests = newTestService(t, answerHandler("other"), cachePath)
+	p = newTestHTTPProvider(t, service)
+	if err := p.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if valid, err := p.Valid(context.Background(), "tipsy"); err != nil || !valid {
+		t.Errorf("Valid(tipsy) = %v, %v, want true, nil", valid, err)
+	}
+	if got := p.Answer(); got != "flint" {
+		t.Errorf("Answer() from the cache = %q, want %q", got, "flint")
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("service got %d requests, want 0", got)
+	}
+}
+
+func TestHTTPProviderOffline(t *testing.T) {
+	srv := httptest.NewServer(answerHandler("flint"))
+	srv.Close()
+	service, err := NewWordService(ServiceOptions{URL: srv.URL, Interval: time.Nanosecond})
+	if err != nil {
+		t.Fatalf("NewWordService: %v", err)
+	}
+	p := newTestHTTPProvider(t, service)
+	if err := p.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if !errors.Is(p.Fallback(), ErrWordService) {
+		t.Errorf("Fallback() = %v, want %v", p.Fallback(), ErrWordService)
+	}
+	if want := localAnswer(t, newTestHTTPProvider(t, service), testPuzzle); p.Answer() != want {
+		t.Errorf("Answer() = %q, want the local answer %q", p.Answer(), want)
+	}
+	if p.PuzzleNumber() != testPuzzle {
+		t.Errorf("PuzzleNumber() = %d, want %d", p.PuzzleNumber(), testPuzzle)
+	}
+	tests := []struct {
+		word string
+		want bool
+	}{
+		{"tipsy", true},
+		{"pious", true},
+		{"flint", false},
+	}
+	for _, tt := range tests {
+		if got, err := p.Valid(context.Background(), tt.word); err != nil || got != tt.want {
+			t.Errorf("Valid(%q) = %v, %v, want %v from the local word list", tt.word, got, err, tt.want)
+		}
+	}
+}
+
+func TestHTTPProviderRateLimited(t *testing.T) {
+	service, requests := newTestService(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, "")
+	p := newTestHTTPProvider(t, service)
+	if err := p.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	if !errors.Is(p.Fallback(), ErrRateLimited) {
+		t.Errorf("Fallback() = %v, want %v", p.Fallback(), ErrRateLimited)
+	}
+	if until := time.Until(service.blockedUntil); until < 110*time.Second || until > 120*time.Second {
+		t.Errorf("service blocked for %v, want the Retry-After delay of 120s", until)
+	}
+	// the service is not called again before the delay has passed
+	if valid, err := p.Valid(context.Background(), "tipsy"); err != nil || !valid {
+		t.Errorf("Valid(tipsy) = %v, %v, want true, nil from the local word list", valid, err)
+	}
+	if got := requests.Load(); got != 1 {
+		t.Errorf("service got %d requests, want 1", got)
+	}
+}
+
+func TestHTTPProviderRejectedAnswer(t *testing.T) {
+	tests := []struct {
+		name string
+		word string
+	}{
+		{"empty", ""},
+		{"too long", "flints"},
+		{"too short", "flit"},
+		{"not in the alphabet", "fl1nt"},
+		{"other alphabet", "λόγος"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cachePath := filepath.Join(t.TempDir(), "cache.json")
+			service, requests := newTestService(t, answerHandler(tt.word), cachePath)
+			p := newTestHTTPProvider(t, service)
+			if err := p.Init(context.Background()); err != nil {
+				t.Fatalf("Init: %v", err)
+			}
+			if !errors.Is(p.Fallback(), ErrWordService) {
+				t.Errorf("Fallback() = %v, want %v", p.Fallback(), ErrWordService)
+			}
+			if want := localAnswer(t, newTestHTTPProvider(t, service), testPuzzle); p.Answer() != want {
+				t.Errorf("Answer() = %q, want the local answer %q", p.Answer(), want)
+			}
+			if _, err := os.Stat(cachePath); !errors.Is(err, os.ErrNotExist) {
+				t.Errorf("rejected answer was cached: %v", err)
+			}
+			// the service is asked again for the next game
+			if err := p.Init(context.Background()); err != nil {
+				t.Fatalf("Init: %v", err)
+			}
+			if got := requests.Load(); got != 2 {
+				t.Errorf("service got %d requests, want 2", got)
+			}
+		})
+	}
+}
+
+func TestHTTPProviderRestore(t *testing.T) {
+	cachePath := filepath.Join(t.TempDir(), "cache.json")
+	cache := serviceCache{Answers: map[string]string{
+		answerKey(1, 5, "en", 0, 1): "Flint",
+		answerKey(2, 5, "en", 0, 1): "flints",
+	}}
+	data, err := json.Marshal(cache)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cachePath, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	service, requests := newTestService(t, answerHandler("other"), cachePath)
+	p := newTestHTTPProvider(t, service)
+
+	started := p.Answer()
+	tests := []struct {
+		id   string
+		want string
+		err  error
+	}{
+		{"1", "flint", nil},
+		{"2", "", ErrUnknownAnswer}, // an invalid cached answer is not used
+		{"5", "", ErrUnknownAnswer}, // not in the cache, the local word list may have another answer
+		{"today", "", ErrUnknownAnswer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			err := p.RestoreAnswer(tt.id)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("RestoreAnswer(%q) = %v, want %v", tt.id, err, tt.err)
+			}
+			if err != nil {
+				if p.Answer() != started {
+					t.Errorf("Answer() = %q after a failed restore, want %q", p.Answer(), started)
+				}
+				return
+			}
+			if p.Answer() != tt.want {
+				t.Errorf("Answer() = %q, want %q", p.Answer(), tt.want)
+			}
+			if p.AnswerID() != tt.id {
+				t.Errorf("AnswerID() = %q, want %q", p.AnswerID(), tt.id)
+			}
+			if p.Fallback() != nil {
+				t.Errorf("Fallback() = %v, want nil", p.Fallback())
+			}
+			started = p.Answer()
+		})
+	}
+	if got := requests.Load(); got != 0 {
+		t.Errorf("service got %d requests, want 0: restoring only reads the cache", got)
+	}
+}
+
+func TestHTTPProviderRestoreMemoryCache(t *testing.T) {
+	service, _ := newTestService(t, answerHandler("flint"), "")
+	p := newTestHTTPProvider(t, service)
+	if err := p.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	id := p.AnswerID()
+	if err := p.RestoreAnswer(id); err != nil || p.Answer() != "flint" {
+		t.Errorf("RestoreAnswer(%q) = %q, %v, want %q from the cache", id, p.Answer(), err, "flint")
+	}
+	// after a restart the cache is empty and the service may have moved on
+	restarted, _ := newTestService(t, answerHandler("other"), "")
+	r := newTestHTTPProvider(t, restarted)
+	if err := r.RestoreAnswer(id); !errors.Is(err, ErrUnknownAnswer) {
+		t.Errorf("RestoreAnswer(%q) after a restart = %v, want %v", id, err, ErrUnknownAnswer)
+	}
+}
@@ -0,0 +1,518 @@
+// hunspell.go checks guesses with a Hunspell spell-checking dictionary, expanding the affix rules of its stems
+package engine
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidDictionary   = errors.New("invalid hunspell dictionary")
+	ErrUnsupportedEncoding = errors.New("unsupported encoding")
+)
+
+// utf8BOM is the byte order mark some editors write at the start of UTF-8 files
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// Hunspell is a WordChecker backed by a Hunspell dictionary: a .dic file of stems with affix flags and an .aff file
+// with the prefix and suffix rules the flags stand for. Every form of the stems with the game's word length is
+// generated when the dictionary is loaded, so inflected forms such as plurals are valid guesses.
+// Compound words are not generated, and capitalized entries such as names are left out.
+type Hunspell struct {
+	words    map[string]bool // valid forms, folded
+	alphabet Alphabet
+}
+
+// Valid reports whether the dictionary has a form of the word
+func (h *Hunspell) Valid(_ context.Context, word string) (bool, error) {
+	return h.words[h.alphabet.Fold(word)], nil
+}
+
+// Len returns the number of valid words
+func (h *Hunspell) Len() int {
+	return len(h.words)
+}
+
+// LoadHunspell loads the Hunspell dictionary at dicPath with the affix file at affPath,
+// keeping the words of the given length written in the alphabet
+func LoadHunspell(dicPath, affPath string, length int, a Alphabet) (*Hunspell, error) {
+	aff, err := os.Open(affPath)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck
+	defer aff.Close()
+	dic, err := os.Open(dicPath)
+	if err != nil {
+		return nil, err
+	}
+	//nolint:errcheck
+	defer dic.Close()
+	return ParseHunspell(dic, aff, length, a)
+}
+
+// ParseHunspell reads a Hunspell dictionary and its affix file, keeping the words of the given length written in
+// the alphabet. Both files use the encoding named by the SET directive of the affix file, ISO8859-1 by default.
+func ParseHunspell(dic, aff io.Reader, length int, a Alphabet) (*Hunspell, error) {
+	a = a.orDefault()
+	x, err := parseAffixes(aff)
+	if err != nil {
+		return nil, fmt.Errorf("affix file: %w", err)
+	}
+	h := &Hunspell{words: map[string]bool{}, alphabet: a}
+	add := func(w string) {
+		if x.ignore != "" {
+			w = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(x.ignore, r) {
+					return -1
+				}
+				return r
+			}, w)
+		}
+		// names and abbreviations are not valid guesses
+		if strings.IndexFunc(w, unicode.IsUpper) >= 0 {
+			return
+		}
+		w = a.Fold(w)
+		if utf8.RuneCountInString(w) != length || strings.IndexFunc(w, func(r rune) bool { return !a.Contains(r) }) >= 0 {
+			return
+		}
+		h.words[w] = true
+	}
+	forbidden := map[string]bool{}
+	scanner := bufio.NewScanner(dic)
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := scanner.Bytes()
+		if line == 1 {
+			raw = bytes.TrimPrefix(raw, utf8BOM)
+		}
+		text := strings.TrimSpace(x.decode(raw))
+		if line == 1 {
+			// the first line holds the approximate number of entries
+			if _, err := strconv.Atoi(text); err == nil {
+				continue
+			}
+		}
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		word, field := splitEntry(text)
+		flags, err := x.parseFlags(field)
+		if err != nil {
+			return nil, fmt.Errorf("dictionary: line %d: %w", line, err)
+		}
+		if x.has(flags, x.forbidden) {
+			forbidden[a.Fold(word)] = true
+			continue
+		}
+		if x.has(flags, x.onlyInCompound) {
+			continue
+		}
+		x.expand(word, flags, add)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("dictionary: %w", err)
+	}
+	for w := range forbidden {
+		delete(h.words, w)
+	}
+	return h, nil
+}
+
+// splitEntry splits a .dic entry into its word and its flags, dropping the morphological fields.
+// A slash in the word is escaped as \/.
+func splitEntry(entry string) (word, flags string) {
+	entry = strings.Fields(entry)[0]
+	for i := 0; i < len(entry); i++ {
+		switch entry[i] {
+		case '\\':
+			i++
+		case '/':
+			return strings.ReplaceAll(entry[:i], `\/`, "/"), entry[i+1:]
+		}
+	}
+	return strings.ReplaceAll(entry, `\/`, "/"), ""
+}
+
+// affixes holds the rules of an affix file
+type affixes struct {
+	decode         func([]byte) string
+	flagType       string                 // how flags are written: one character each (default), long, num or UTF-8
+	aliases        [][]string             // flag sets numbered by the AF directive, starting at 1
+	classes        map[string]*affixClass // affix classes by flag
+	needAffix      string                 // flag of the stems that are only valid with an affix
+	forbidden      string                 // flag of the words that are not valid, even as the form of another stem
+	onlyInCompound string                 // flag of the stems and affixes only used inside compound words
+	ignore         string                 // characters removed from the words
+}
+
+// affixClass is the set of prefix or suffix rules named by a flag
+type affixClass struct {
+	prefix bool
+	cross  bool // the prefixes and suffixes of a stem can be combined
+	rules  []affixRule
+	left   int // number of rules not read yet
+}
+
+// affixRule replaces strip with add at the start of a word for a prefix, or at the end for a suffix,
+// when the word matches the condition there
+type affixRule struct {
+	strip, add string
+	cont       []string // flags of the affixes that can follow this one
+	cond       condition
+}
+
+// parseAffixes reads an affix file
+func parseAffixes(r io.Reader) (*affixes, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, utf8BOM)
+	x := &affixes{classes: map[string]*affixClass{}}
+	if x.decode, err = decoder(encodingOf(data)); err != nil {
+		return nil, err
+	}
+	aliases := 0 // number of AF lines left to read
+	for i, raw := range bytes.Split(data, []byte("\n")) {
+		fields := strings.Fields(x.decode(raw))
+		if len(fields) < 2 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		switch fields[0] {
+		case "FLAG":
+			x.flagType = fields[1]
+		case "NEEDAFFIX", "PSEUDOROOT":
+			x.needAffix = fields[1]
+		case "FORBIDDENWORD":
+			x.forbidden = fields[1]
+		case "ONLYINCOMPOUND":
+			x.onlyInCompound = fields[1]
+		case "IGNORE":
+			x.ignore = fields[1]
+		case "AF":
+			if aliases == 0 && x.aliases == nil {
+				n, err := strconv.Atoi(fields[1])
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d: invalid number of flag aliases %q", ErrInvalidDictionary, i+1, fields[1])
+				}
+				aliases, x.aliases = n, [][]string{}
+				continue
+			}
+			if aliases == 0 {
+				return nil, fmt.Errorf("%w: line %d: more flag aliases than announced", ErrInvalidDictionary, i+1)
+			}
+			flags, err := x.splitFlags(fields[1])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			x.aliases = append(x.aliases, flags)
+			aliases--
+		case "PFX", "SFX":
+			if err := x.parseAffix(fields); err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+		}
+	}
+	return x, nil
+}
+
+// parseAffix reads the header of an affix class, such as SFX A Y 2, or one of its rules, such as SFX A y ies [^aeiou]y
+func (x *affixes) parseAffix(fields []string) error {
+	flag := fields[1]
+	c := x.classes[flag]
+	if c == nil || c.left == 0 {
+		if len(fields) < 4 || (fields[2] != "Y" && fields[2] != "N") {
+			return fmt.Errorf("%w: invalid affix header %q", ErrInvalidDictionary, strings.Join(fields, " "))
+		}
+		n, err := strconv.Atoi(fields[3])
+		if err != nil {
+			return fmt.Errorf("%w: invalid number of rules %q", ErrInvalidDictionary, fields[3])
+		}
+		if c == nil {
+			c = &affixClass{prefix: fields[0] == "PFX", cross: fields[2] == "Y"}
+			x.classes[flag] = c
+		}
+		c.left = n
+		return nil
+	}
+	if len(fields) < 4 {
+		return fmt.Errorf("%w: invalid affix rule %q", ErrInvalidDictionary, strings.Join(fields, " "))
+	}
+	r := affixRule{strip: fields[2], add: fields[3]}
+	if add, cont, ok := strings.Cut(r.add, "/"); ok {
+		flags, err := x.parseFlags(cont)
+		if err != nil {
+			return err
+		}
+		r.add, r.cont = add, flags
+	}
+	if r.strip == "0" {
+		r.strip = ""
+	}
+	if r.add == "0" {
+		r.add = ""
+	}
+	cond := "."
+	if len(fields) > 4 {
+		cond = fields[4]
+	}
+	var err error
+	if r.cond, err = parseCondition(cond); err != nil {
+		return err
+	}
+	c.rules = append(c.rules, r)
+	c.left--
+	return nil
+}
+
+// parseFlags splits the flags of a word or an affix, or looks them up when they are given as an alias number
+func (x *affixes) parseFlags(s string) ([]string, error) {
+	if s == "" {
+		return nil, nil
+	}
+	if x.aliases != nil {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > len(x.aliases) {
+			return nil, fmt.Errorf("%w: unknown flag alias %q", ErrInvalidDictionary, s)
+		}
+		return x.aliases[n-1], nil
+	}
+	return x.splitFlags(s)
+}
+
+// splitFlags splits flags written in the flag type of the affix file
+func (x *affixes) splitFlags(s string) ([]string, error) {
+	var flags []string
+	switch x.flagType {
+	case "long":
+		rs := []rune(s)
+		if len(rs)%2 != 0 {
+			return nil, fmt.Errorf("%w: odd number of characters in long flags %q", ErrInvalidDictionary, s)
+		}
+		for i := 0; i < len(rs); i += 2 {
+			flags = append(flags, string(rs[i:i+2]))
+		}
+	case "num":
+		flags = strings.Split(s, ",")
+	default:
+		for _, r := range s {
+			flags = append(flags, string(r))
+		}
+	}
+	return flags, nil
+}
+
+// has reports whether flags contain flag, a directive that is not set is never contained
+func (x *affixes) has(flags []string, flag string) bool {
+	return flag != "" && slices.Contains(flags, flag)
+}
+
+// expand passes every form of a stem to add: the stem itself, the stem with each of its suffixes and prefixes,
+// with a prefix and a suffix when both allow it, and with the affixes that may follow a suffix
+func (x *affixes) expand(stem string, flags []string, add func(string)) {
+	if !x.has(flags, x.needAffix) {
+		add(stem)
+	}
+	for _, f := range flags {
+		c := x.classes[f]
+		if c == nil {
+			continue
+		}
+		for _, r := range c.rules {
+			w, ok := r.apply(stem, c.prefix)
+			if !ok || x.has(r.cont, x.onlyInCompound) {
+				continue
+			}
+			if !x.has(r.cont, x.needAffix) {
+				add(w)
+			}
+			if c.prefix {
+				continue
+			}
+			for _, next := range r.cont {
+				x.applyClass(w, next, false, add)
+			}
+			if c.cross {
+				for _, pf := range flags {
+					x.applyClass(w, pf, true, add)
+				}
+			}
+		}
+	}
+}
+
+// applyClass passes the forms of w with the rules of the class named by flag to add.
+// With crossOnly, only the prefixes that combine with suffixes are applied.
+func (x *affixes) applyClass(w, flag string, crossOnly bool, add func(string)) {
+	c := x.classes[flag]
+	if c == nil || (crossOnly && (!c.prefix || !c.cross)) {
+		return
+	}
+	for _, r := range c.rules {
+		if w2, ok := r.apply(w, c.prefix); ok {
+			add(w2)
+		}
+	}
+}
+
+// apply returns word with the affix, or false if the rule does not apply to it
+func (r affixRule) apply(word string, prefix bool) (string, bool) {
+	if len(r.strip) >= len(word) {
+		return "", false
+	}
+	rs := []rune(word)
+	if prefix {
+		if !strings.HasPrefix(word, r.strip) || !r.cond.matchStart(rs) {
+			return "", false
+		}
+		return r.add + word[len(r.strip):], true
+	}
+	if !strings.HasSuffix(word, r.strip) || !r.cond.matchEnd(rs) {
+		return "", false
+	}
+	return word[:len(word)-len(r.strip)] + r.add, true
+}
+
+// condition is the pattern a word must match at the start for a prefix, or at the end for a suffix,
+// one character class per letter
+type condition []charClass
+
+// charClass matches a letter: any letter for ".", or the letters of a set such as [aeiou] or [^aeiou]
+type charClass struct {
+	any     bool
+	negated bool
+	runes   []rune
+}
+
+// parseCondition parses the condition of an affix rule
+func parseCondition(s string) (condition, error) {
+	if s == "." {
+		return nil, nil
+	}
+	var c condition
+	rs := []rune(s)
+	for i := 0; i < len(rs); i++ {
+		switch rs[i] {
+		case '.':
+			c = append(c, charClass{any: true})
+		case '[':
+			end := slices.Index(rs[i:], ']')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed bracket in condition %q", ErrInvalidDictionary, s)
+			}
+			cl := charClass{runes: rs[i+1 : i+end]}
+			if len(cl.runes) > 0 && cl.runes[0] == '^' {
+				cl.negated, cl.runes = true, cl.runes[1:]
+			}
+			c = append(c, cl)
+			i += end
+		default:
+			c = append(c, charClass{runes: rs[i : i+1]})
+		}
+	}
+	return c, nil
+}
+
+func (cl charClass) match(r rune) bool {
+	return cl.any || slices.Contains(cl.runes, r) != cl.negated
+}
+
+// matchStart reports whether the start of word matches the condition
+func (c condition) matchStart(word []rune) bool {
+	if len(c) > len(word) {
+		return false
+	}
+	for i, cl := range c {
+		if !cl.match(word[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchEnd reports whether the end of word matches the condition
+func (c condition) matchEnd(word []rune) bool {
+	if len(c) > len(word) {
+		return false
+	}
+	offset := len(word) - len(c)
+	for i, cl := range c {
+		if !cl.match(word[offset+i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// encodingOf returns the encoding named by the SET directive of an affix file, or ISO8859-1 if there is none
+func encodingOf(aff []byte) string {
+	for line := range bytes.Lines(aff) {
+		if fields := bytes.Fields(line); len(fields) >= 2 && string(fields[0]) == "SET" {
+			return string(fields[1])
+		}
+	}
+	return "ISO8859-1"
+}
+
+// decoder returns the function converting text in the encoding to UTF-8
+func decoder(encoding string) (func([]byte) string, error) {
+	switch strings.ReplaceAll(strings.ToUpper(encoding), "ISO-", "ISO") {
+	case "UTF-8":
+		return func(b []byte) string { return string(b) }, nil
+	case "ISO8859-1":
+		return singleByte(nil), nil
+	case "ISO8859-7":
+		return singleByte(iso88597), nil
+	case "ISO8859-15":
+		return singleByte(iso885915), nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupportedEncoding, encoding)
+}
+
+// singleByte returns the decoder of a single-byte encoding that matches ISO 8859-1 except for the bytes in table
+func singleByte(table map[byte]rune) func([]byte) string {
+	return func(b []byte) string {
+		var sb strings.Builder
+		for _, c := range b {
+			if r, ok := table[c]; ok {
+				sb.WriteRune(r)
+			} else {
+				sb.WriteRune(rune(c))
+			}
+		}
+		return sb.String()
+	}
+}
+
+// iso88597 holds the Greek letters of ISO 8859-7, which follow the order of Unicode from 0xB4
+var iso88597 = func() map[byte]rune {
+	table := map[byte]rune{}
+	for c := 0xB4; c <= 0xFE; c++ {
+		switch c {
+		case 0xB7, 0xBB, 0xBD: // punctuation kept from ISO 8859-1
+		case 0xD2:
+			table[byte(c)] = utf8.RuneError
+		default:
+			table[byte(c)] = rune(c) + 0x2D0
+		}
+	}
+	return table
+}()
+
+// iso885915 holds the characters of ISO 8859-15 that differ from ISO 8859-1
+var iso885915 = map[byte]rune{
+	0xA4: '€', 0xA6: 'Š', 0xA8: 'š', 0xB4: 'Ž', 0xB8: 'ž', 0xBC: 'Œ', 0xBD: 'œ', 0xBE: 'Ÿ',
+}
@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParseHunspell(t *testing.T) {
+	const suffixes = `SET UTF-8
+SFX S Y 2
+SFX S 0 s [^sxz]
+SFX S 0 es [sxz]
+`
+	const crossProducts = `SET UTF-8
+PFX U Y 1
+PFX U 0 un .
+PFX R N 1
+PFX R 0 re .
+SFX D Y 2
+SFX D 0 ed [^e]
+SFX D 0 d e
+`
+	const continuations = `SET UTF-8
+NEEDAFFIX X
+PFX U Y 1
+PFX U 0 un .
+SFX B Y 1
+SFX B 0 able/U .
+SFX E N 1
+SFX E 0 e .
+`
+	const aliases = `SET UTF-8
+AF 2
+AF S
+AF SU
+PFX U Y 1
+PFX U 0 un .
+SFX S Y 2
+SFX S 0 s [^sxz]
+SFX S 0 es [sxz]
+`
+	const longFlags = `SET UTF-8
+FLAG long
+PFX Re Y 1
+PFX Re 0 re .
+SFX Ss Y 1
+SFX Ss 0 s .
+`
+	const numFlags = `SET UTF-8
+FLAG num
+SFX 101 Y 1
+SFX 101 0 ing .
+SFX 7 Y 1
+SFX 7 0 s .
+`
+	const forbidden = `SET UTF-8
+FORBIDDENWORD !
+SFX S Y 1
+SFX S 0 s .
+`
+	// λόγ/1 with the suffixes ος and οι, written in ISO 8859-7 with an alias of long flags
+	const greek = "SET ISO8859-7\nFLAG long\nAF 1\nAF Os\nSFX Os Y 2\nSFX Os 0 \xef\xf2 .\nSFX Os 0 \xef\xe9 .\n"
+	tests := []struct {
+		name     string
+		aff      string
+		dic      string
+		length   int
+		alphabet Alphabet
+		valid    []string
+		invalid  []string
+	}{
+		{"suffixes", suffixes, "2\nlock/S\nbox/S\n", 5, Latin, []string{"locks", "boxes"}, []string{"lockes", "boxs"}},
+		{"stems", suffixes, "2\nlock/S\nbox/S\n", 4, Latin, []string{"lock", "LOCK"}, []string{"box"}},
+		{"suffix condition", crossProducts, "2\nlock/D\nbake/D\n", 5, Latin, []string{"baked"}, []string{"bakeed"}},
+		{"prefixes", crossProducts, "1\nlock/UDR\n", 6, Latin, []string{"unlock", "relock", "locked"}, nil},
+		{"cross product", crossProducts, "1\nlock/UDR\n", 8, Latin, []string{"unlocked"}, []string{"relocked"}},
+		{"continuation", continuations, "1\ndo/B\n", 8, Latin, []string{"undoable"}, nil},
+		{"continuation alone", continuations, "1\ndo/B\n", 6, Latin, []string{"doable"}, nil},
+		{"continuation only after the suffix", continuations, "1\ndo/B\n", 4, Latin, nil, []string{"undo"}},
+		{"stem needing an affix", continuations, "1\nhav/XE\n", 4, Latin, []string{"have"}, nil},
+		{"stem needing an affix alone", continuations, "1\nhav/XE\n", 3, Latin, nil, []string{"hav"}},
+		{"flag aliases", aliases, "2\nlock/1\nbox/2\n", 5, Latin, []string{"locks", "boxes", "unbox"}, []string{"unlock"}},
+		{"long flags", longFlags, "1\ntell/ReSs\n", 7, Latin, []string{"retells"}, nil},
+		{"long flags alone", longFlags, "1\ntell/ReSs\n", 5, Latin, []string{"tells"}, nil},
+		{"numeric flags", numFlags, "1\njump/101,7\n", 7, Latin, []string{"jumping"}, nil},
+		{"numeric flags alone", numFlags, "1\njump/101,7\n", 5, Latin, []string{"jumps"}, []string{"jumpings"}},
+		{"forbidden words", forbidden, "2\nword/S\nwords/!\n", 5, Latin, nil, []string{"words"}},
+		{"names", forbidden, "\xef\xbb\xbf2\nParis\nhotel/S\n", 5, Latin, []string{"hotel"}, []string{"paris"}},
+		{"ISO 8859-7", greek, "1\n\xeb\xfc\xe3/1\n", 5, Greek, []string{"λόγος", "λόγοι", "λογοσ"}, []string{"λόγ"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, err := ParseHunspell(strings.NewReader(tt.dic), strings.NewReader(tt.aff), tt.length, tt.alphabet)
+			if err != nil {
+				t.Fatalf("ParseHunspell: %v", err)
+			}
+			for _, w := range tt.valid {
+				if ok, _ := h.Valid(context.Background(), w); !ok {
+					t.Errorf("Valid(%q) = false, want true", w)
+				}
+			}
+			for _, w := range tt.invalid {
+				if ok, _ := h.Valid(context.Background(), w); ok {
+					t.Errorf("Valid(%q) = true, want false", w)
+				}
+			}
+		})
+	}
+}
+
+func TestParseHunspellErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		aff  string
+		dic  string
+		err  error
+	}{
+		{"unsupported encoding", "SET KOI8-R\n", "1\nword\n", ErrUnsupportedEncoding},
+		{"unknown flag alias", "AF 1\nAF S\n", "1\nword/2\n", ErrInvalidDictionary},
+		{"too many flag aliases", "AF 1\nAF S\nAF T\n", "1\nword/1\n", ErrInvalidDictionary},
+		{"odd long flags", "FLAG long\n", "1\nword/Abc\n", ErrInvalidDictionary},
+		{"invalid affix header", "SFX S X 1\n", "1\nword/S\n", ErrInvalidDictionary},
+		{"unclosed condition", "SFX S Y 1\nSFX S 0 s [aeiou\n", "1\nword/S\n", ErrInvalidDictionary},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseHunspell(strings.NewReader(tt.dic), strings.NewReader(tt.aff), 5, Latin)
+			if !errors.Is(err, tt.err) {
+				t.Errorf("ParseHunspell() = %v, want %v", err, tt.err)
+			}
+		})
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	tests := []struct {
+		encoding string
+		in       string
+		want     string
+	}{
+		{"UTF-8", "λόγος", "λόγος"},
+		{"ISO8859-1", "caf\xe9 \xa4", "café ¤"},
+		{"ISO-8859-1", "na\xefve", "naïve"},
+		{"ISO8859-15", "c\xbdur \xa4", "cœur €"},
+		{"ISO8859-7", "\xeb\xfc\xe3\xef\xf2", "λόγος"},
+		{"iso-8859-7", "\xc1\xe8\xde\xed\xe1", "Αθήνα"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.encoding, func(t *testing.T) {
+			decode, err := decoder(tt.encoding)
+			if err != nil {
+				t.Fatalf("decoder(%q): %v", tt.encoding, err)
+			}
+			if got := decode([]byte(tt.in)); got != tt.want {
+				t.Errorf("decode(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,64 @@
+// letter.go defines the per-letter feedback of a guess
+package engine
+
+// LetterState is the feedback for a single letter of a guess
+type LetterState int
+
+// the order matters: a lower state reveals more, so the keyboard keeps the lowest state seen for a letter
+const (
+	Matched    LetterState = iota // letter is in the correct position
+	Exists                        // letter is in the word but not in the correct position
+	NotMatched                    // letter is not in the word
+	NotChecked                    // letter has not been checked yet
+	Revealed                      // letter has been revealed by a hint, only shown on tiles and never part of a guess feedback
+)
+
+// map to get string representation of each state
+var states = map[LetterState]string{
+	Matched:    "matched",
+	Exists:     "exists",
+	NotMatched: "notMatched",
+	NotChecked: "notChecked",
+	Revealed:   "revealed",
+}
+
+func (s LetterState) String() string {
+	return states[s]
+}
+
+// Letter is a letter of a guess with its feedback
+type Letter struct {
+	Rune  rune
+	State LetterState
+}
+
+// Feedback is the result of a guess, one letter per position
+type Feedback []Letter
+
+// Word returns the guessed word
+func (f Feedback) Word() string {
+	rs := make([]rune, len(f))
+	for i, l := range f {
+		rs[i] = l.Rune
+	}
+	return string(rs)
+}
+
+// Solved reports whether every letter is in the correct position
+func (f Feedback) Solved() bool {
+	for _, l := range f {
+		if l.State != Matched {
+			return false
+		}
+	}
+	return len(f) > 0
+}
+
+// States returns the state of each letter
+func (f Feedback) States() []LetterState {
+	s := make([]LetterState, len(f))
+	for i, l := range f {
+		s[i] = l.State
+	}
+	return s
+}
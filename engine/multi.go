@@ -0,0 +1,326 @@
+// multi.go plays several boards at once, each with its own answer
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+var ErrNoBoards = errors.New("at least one board is required")
+
+// MultiGame is a game played on several boards at once, such as Dordle or Quordle.
+// Every guess is applied to each board that has not been solved yet, solved boards are frozen.
+// The game is won when every board is solved and lost when the shared attempts run out.
+// A MultiGame with a single board plays exactly like a Game.
+type MultiGame struct {
+	boards     []*Game
+	words      []string // guesses submitted so far
+	hintBudget int
+}
+
+// NewMultiGame creates a game with one board per answer provider.
+// Every board gets the same options, the hint budget is shared by all boards.
+func NewMultiGame(providers []AnswerProvider, opts Options) (*MultiGame, error) {
+	if len(providers) == 0 {
+		return nil, ErrNoBoards
+	}
+	m := &MultiGame{hintBudget: opts.Hints}
+	for i, p := range providers {
+		b, err := NewGame(p, opts)
+		if err != nil {
+			return nil, fmt.Errorf("board %d: %w", i+1, err)
+		}
+		m.boards = append(m.boards, b)
+	}
+	return m, nil
+}
+
+// Boards returns the boards of the game
+func (m *MultiGame) Boards() []*Game {
+	return m.boards
+}
+
+// Init initializes the answer providers of every board, picking again when a board gets the answer
+// of an earlier one. It may perform I/O, so it should not be called from a UI loop.
+// It returns the error of the first board whose provider cannot pick an answer.
+func (m *MultiGame) Init(ctx context.Context) error {
+	for i, b := range m.boards {
+		if err := b.Init(ctx); err != nil {
+			return fmt.Errorf("board %d: %w", i+1, err)
+		}
+		// a few attempts are enough unless the answer list is smaller than the number of boards
+		for range 10 {
+			if !slices.ContainsFunc(m.boards[:i], func(o *Game) bool { return o.provider.Answer() == b.provider.Answer() }) {
+				break
+			}
+			if err := b.Init(ctx); err != nil {
+				return fmt.Errorf("board %d: %w", i+1, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Start starts every board with the answer picked by its provider
+func (m *MultiGame) Start() {
+	for _, b := range m.boards {
+		b.Start()
+	}
+}
+
+// State returns Won when every board is solved, Lost when the attempts ran out before that,
+// and Loading or Playing otherwise
+func (m *MultiGame) State() State {
+	switch {
+	case slices.ContainsFunc(m.boards, func(b *Game) bool { return b.State() == Loading }):
+		return Loading
+	case slices.ContainsFunc(m.boards, (*Game).IsLost):
+		return Lost
+	case !slices.ContainsFunc(m.boards, func(b *Game) bool { return !b.IsWon() }):
+		return Won
+	}
+	return Playing
+}
+
+func (m *MultiGame) IsWon() bool {
+	return m.State() == Won
+}
+
+func (m *MultiGame) IsLost() bool {
+	return m.State() == Lost
+}
+
+func (m *MultiGame) InProgress() bool {
+	return m.State() == Playing
+}
+
+func (m *MultiGame) IsFinished() bool {
+	return m.IsWon() || m.IsLost()
+}
+
+// Answers returns the answer of each board
+func (m *MultiGame) Answers() []string {
+	answers := make([]string, len(m.boards))
+	for i, b := range m.boards {
+		answers[i] = b.Answer()
+	}
+	return answers
+}
+
+func (m *MultiGame) Rows() int {
+	return m.boards[0].Rows()
+}
+
+func (m *MultiGame) WordLength() int {
+	return m.boards[0].WordLength()
+}
+
+func (m *MultiGame) Alphabet() Alphabet {
+	return m.boards[0].Alphabet()
+}
+
+func (m *MultiGame) HardMode() bool {
+	return m.boards[0].HardMode()
+}
+
+func (m *MultiGame) PuzzleNumber() (int, bool) {
+	return m.boards[0].PuzzleNumber()
+}
+
+func (m *MultiGame) Mode() string {
+	return m.boards[0].Mode()
+}
+
+// Fallback returns the reason the first board that falls back to a local word list does so,
+// nil if no board does
+func (m *MultiGame) Fallback() error {
+	for _, b := range m.boards {
+		if fp, ok := b.provider.(FallbackProvider); ok {
+			if err := fp.Fallback(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (m *MultiGame) Difficulty() string {
+	return m.boards[0].Difficulty()
+}
+
+// Words returns the guesses submitted so far
+func (m *MultiGame) Words() []string {
+	return m.words
+}
+
+// CanToggleHardMode reports whether hard mode can be switched, which is only allowed before the first guess
+func (m *MultiGame) CanToggleHardMode() bool {
+	return !slices.ContainsFunc(m.boards, func(b *Game) bool { return !b.CanToggleHardMode() })
+}
+
+// ToggleHardMode switches hard mode on or off on every board if no guess has been submitted yet
+func (m *MultiGame) ToggleHardMode() bool {
+	if !m.CanToggleHardMode() {
+		return false
+	}
+	for _, b := range m.boards {
+		b.ToggleHardMode()
+	}
+	return true
+}
+
+// Validate checks that word can be submitted as the next guess on every board that is not solved yet
+func (m *MultiGame) Validate(ctx context.Context, word string) error {
+	if !m.InProgress() {
+		return ErrNotPlaying
+	}
+	for _, b := range m.boards {
+		if !b.InProgress() {
+			continue
+		}
+		if err := b.Validate(ctx, word); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Guess validates and submits a guess on every board that is not solved yet.
+// It returns the feedback for each board, nil for the boards that were already solved.
+func (m *MultiGame) Guess(ctx context.Context, word string) ([]Feedback, error) {
+	if err := m.Validate(ctx, word); err != nil {
+		return nil, err
+	}
+	return m.Apply(word)
+}
+
+// Apply submits a guess that Validate has accepted on every board that is not solved yet, without checking it again.
+// It returns the feedback for each board, nil for the boards that were already solved.
+func (m *MultiGame) Apply(word string) ([]Feedback, error) {
+	if !m.InProgress() {
+		return nil, ErrNotPlaying
+	}
+	fbs := make([]Feedback, len(m.boards))
+	for i, b := range m.boards {
+		if !b.InProgress() {
+			continue
+		}
+		fb, err := b.Apply(word)
+		if err != nil {
+			return nil, fmt.Errorf("board %d: %w", i+1, err)
+		}
+		fbs[i] = fb
+	}
+	m.words = append(m.words, word)
+	return fbs, nil
+}
+
+// Reset clears the guesses and starts every board again with the same answer, keeping the hints used
+func (m *MultiGame) Reset() {
+	m.words = nil
+	for _, b := range m.boards {
+		b.Reset()
+	}
+}
+
+// Hints returns the hints given so far on every board
+func (m *MultiGame) Hints() []Hint {
+	var hints []Hint
+	for i, b := range m.boards {
+		for _, h := range b.Hints() {
+			h.Board = i
+			hints = append(hints, h)
+		}
+	}
+	return hints
+}
+
+// HintsLeft returns the number of hints that can still be used in this game
+func (m *MultiGame) HintsLeft() int {
+	return max(0, m.hintBudget-len(m.Hints()))
+}
+
+// Hint uses one hint of the shared budget on the first board that is not solved yet
+func (m *MultiGame) Hint(kind HintKind) (Hint, error) {
+	if !m.InProgress() {
+		return Hint{}, ErrNotPlaying
+	}
+	if m.HintsLeft() == 0 {
+		return Hint{}, ErrNoHintsLeft
+	}
+	i := slices.IndexFunc(m.boards, (*Game).InProgress)
+	h, err := m.boards[i].Hint(kind)
+	if err != nil {
+		return Hint{}, err
+	}
+	h.Board = i
+	return h, nil
+}
+
+// Snapshot returns the game state to be saved, or false if an answer provider cannot restore its answer.
+// The answer ids of the boards are separated by commas.
+func (m *MultiGame) Snapshot() (Snapshot, bool) {
+	ids := make([]string, len(m.boards))
+	for i, b := range m.boards {
+		s, ok := b.Snapshot()
+		if !ok {
+			return Snapshot{}, false
+		}
+		ids[i] = s.AnswerID
+	}
+	return Snapshot{
+		Guesses:  slices.Clone(m.words),
+		AnswerID: strings.Join(ids, ","),
+		Mode:     m.Mode(),
+		HardMode: m.HardMode(),
+		Hints:    m.Hints(),
+		Language: m.Alphabet().Language,
+	}, true
+}
+
+// boardSnapshot returns the part of a snapshot that applies to board i
+func (m *MultiGame) boardSnapshot(s Snapshot, i int) (Snapshot, bool) {
+	ids := strings.Split(s.AnswerID, ",")
+	if len(ids) != len(m.boards) {
+		return Snapshot{}, false
+	}
+	bs := s
+	bs.AnswerID = ids[i]
+	bs.Hints = nil
+	for _, h := range s.Hints {
+		if h.Board == i {
+			h.Board = 0
+			bs.Hints = append(bs.Hints, h)
+		}
+	}
+	return bs, true
+}
+
+// CanRestore reports whether a snapshot fits this game's boards, size, mode and answer providers
+func (m *MultiGame) CanRestore(s Snapshot) bool {
+	for i, b := range m.boards {
+		bs, ok := m.boardSnapshot(s, i)
+		if !ok || !b.CanRestore(bs) {
+			return false
+		}
+	}
+	return true
+}
+
+// Restore replaces the current game with a saved one and restores the answers of every board
+func (m *MultiGame) Restore(s Snapshot) error {
+	if !m.CanRestore(s) {
+		return ErrNotResumable
+	}
+	for i, b := range m.boards {
+		bs, _ := m.boardSnapshot(s, i)
+		if err := b.Restore(bs); err != nil {
+			return fmt.Errorf("board %d: %w", i+1, err)
+		}
+	}
+	m.words = slices.Clone(s.Guesses)
+	return nil
+}
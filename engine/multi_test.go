@@ -0,0 +1,170 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// sequenceProvider picks its answers in order, one per call to Init, and keeps the last one
+type sequenceProvider struct {
+	StaticProvider
+	words []string
+}
+
+func (p *sequenceProvider) Init(ctx context.Context) error {
+	p.Word, p.words = p.words[0], p.words[min(1, len(p.words)-1):]
+	return ctx.Err()
+}
+
+func (p *sequenceProvider) Answer() string {
+	return p.Word
+}
+
+func TestMultiGameInit(t *testing.T) {
+	tests := []struct {
+		name   string
+		boards [][]string // answers picked by the provider of each board, in order
+		want   []string
+	}{
+		{"different answers", [][]string{{"crane"}, {"slate"}}, []string{"crane", "slate"}},
+		{"same answer picked again", [][]string{{"crane"}, {"crane", "crane", "slate"}}, []string{"crane", "slate"}},
+		{"answer of any earlier board", [][]string{{"crane"}, {"slate"}, {"slate", "crane", "pious"}}, []string{"crane", "slate", "pious"}},
+		{"gives up on a single answer", [][]string{{"crane"}, {"crane"}}, []string{"crane", "crane"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var providers []AnswerProvider
+			for _, words := range tt.boards {
+				providers = append(providers, &sequenceProvider{StaticProvider: StaticProvider{Word: words[0]}, words: words})
+			}
+			m, err := NewMultiGame(providers, Options{Rows: 6})
+			if err != nil {
+				t.Fatalf("NewMultiGame: %v", err)
+			}
+			if err := m.Init(context.Background()); err != nil {
+				t.Fatalf("Init: %v", err)
+			}
+			m.Start()
+			if got := m.Answers(); !slices.Equal(got, tt.want) {
+				t.Errorf("Answers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiGameState(t *testing.T) {
+	tests := []struct {
+		name    string
+		rows    int
+		guesses []string
+		want    State
+	}{
+		{"no guesses", 2, nil, Playing},
+		{"one board solved", 2, []string{"crane"}, Playing},
+		{"every board solved", 2, []string{"crane", "slate"}, Won},
+		{"other board lost", 1, []string{"crane"}, Lost},
+		{"every board lost", 1, []string{"pious"}, Lost},
+		{"board lost after the other was solved", 2, []string{"crane", "pious"}, Lost},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestMultiGame(t, []string{"crane", "slate"}, Options{Rows: tt.rows}, tt.guesses...)
+			if got := m.State(); got != tt.want {
+				t.Errorf("State() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// newResumableMultiGame creates a game with one dictionary board per answer, started with the guesses submitted
+func newResumableMultiGame(t *testing.T, answers []string, guesses ...string) *MultiGame {
+	t.Helper()
+	var providers []AnswerProvider
+	for _, a := range answers {
+		p, err := NewDictionaryProvider(answers, nil, Latin)
+		if err != nil {
+			t.Fatalf("NewDictionaryProvider: %v", err)
+		}
+		p.answer = a
+		providers = append(providers, p)
+	}
+	m, err := NewMultiGame(providers, Options{Rows: 6, Hints: 3})
+	if err != nil {
+		t.Fatalf("NewMultiGame: %v", err)
+	}
+	m.Start()
+	for _, w := range guesses {
+		if _, err := m.Apply(w); err != nil {
+			t.Fatalf("Apply(%q): %v", w, err)
+		}
+	}
+	return m
+}
+
+func TestMultiGameSnapshot(t *testing.T) {
+	answers := []string{"crane", "slate", "pious"}
+	m := newResumableMultiGame(t, answers, "crane")
+	if _, err := m.Hint(HintLetter); err != nil {
+		t.Fatalf("Hint: %v", err)
+	}
+	s, ok := m.Snapshot()
+	if !ok {
+		t.Fatal("Snapshot() = false, want true")
+	}
+	if ids := strings.Split(s.AnswerID, ","); len(ids) != len(answers) {
+		t.Fatalf("Snapshot().AnswerID = %q, want %d ids", s.AnswerID, len(answers))
+	}
+	// the answers of the restored game are picked again by the providers, then replaced by the saved ones
+	r := newResumableMultiGame(t, []string{"pious", "crane", "slate"})
+	if err := r.Restore(s); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if got := r.Answers(); !slices.Equal(got, answers) {
+		t.Errorf("Answers() = %v, want %v", got, answers)
+	}
+	if got := r.Words(); !slices.Equal(got, []string{"crane"}) {
+		t.Errorf("Words() = %v, want [crane]", got)
+	}
+	if got, want := r.Hints(), m.Hints(); !slices.Equal(got, want) {
+		t.Errorf("Hints() = %v, want %v", got, want)
+	}
+	if !r.Boards()[0].IsWon() || !r.Boards()[1].InProgress() {
+		t.Errorf("board states = %v, %v, want the first board solved", r.Boards()[0].State(), r.Boards()[1].State())
+	}
+}
+
+func TestBoardSnapshot(t *testing.T) {
+	m := newResumableMultiGame(t, []string{"crane", "slate"})
+	s := Snapshot{
+		Guesses:  []string{"pious"},
+		AnswerID: "a,b",
+		Mode:     ModeRandom,
+		Hints:    []Hint{{Kind: HintLetter, Letter: 'c', Position: 1}, {Kind: HintAbsent, Letter: 'o', Board: 1}},
+	}
+	tests := []struct {
+		name     string
+		answerID string
+		board    int
+		want     Snapshot
+		ok       bool
+	}{
+		{"first board", "a,b", 0, Snapshot{Guesses: []string{"pious"}, AnswerID: "a", Mode: ModeRandom, Hints: []Hint{{Kind: HintLetter, Letter: 'c', Position: 1}}}, true},
+		{"second board", "a,b", 1, Snapshot{Guesses: []string{"pious"}, AnswerID: "b", Mode: ModeRandom, Hints: []Hint{{Kind: HintAbsent, Letter: 'o'}}}, true},
+		{"too few ids", "a", 0, Snapshot{}, false},
+		{"too many ids", "a,b,c", 0, Snapshot{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s.AnswerID = tt.answerID
+			got, ok := m.boardSnapshot(s, tt.board)
+			if ok != tt.ok {
+				t.Fatalf("boardSnapshot(%q, %d) = %v, want %v", tt.answerID, tt.board, ok, tt.ok)
+			}
+			if got.AnswerID != tt.want.AnswerID || !slices.Equal(got.Guesses, tt.want.Guesses) || got.Mode != tt.want.Mode || !slices.Equal(got.Hints, tt.want.Hints) {
+				t.Errorf("boardSnapshot(%q, %d) = %+v, want %+v", tt.answerID, tt.board, got, tt.want)
+			}
+		})
+	}
+}
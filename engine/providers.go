@@ -0,0 +1,427 @@
+// providers defines the answer providers interface and implementations
+package engine
+
+import (
+	"bufio"
+	"cmp"
+	"context"
+	"embed"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io"
+	"io/fs"
+	"math"
+	"math/rand"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrEmptyWordList   = errors.New("empty word list")
+	ErrMixedWordLength = errors.New("words have different lengths")
+	ErrInvalidEntry    = errors.New("invalid word list entry")
+	ErrUnknownAnswer   = errors.New("unknown answer id")
+	ErrNoWordList      = errors.New("no word list")
+)
+
+// default word lists built into the binary, one directory per language and one pair per word length
+// answers-N.txt holds the curated answers, allowed-N.txt holds the extra words that are accepted as guesses
+//
+//go:embed words
+var defaultWordLists embed.FS
+
+// AnswerProvider picks the answer of a game and decides which guesses are valid words.
+// Init and Valid may perform I/O, so they should not be called from a UI loop. They must return when ctx is done,
+// with an error that wraps ctx.Err().
+type AnswerProvider interface {
+	// Init picks a new answer, or returns an error if no answer can be picked
+	Init(ctx context.Context) error
+	// Answer returns the answer picked by Init
+	Answer() string
+	// Valid reports whether word is accepted as a guess, or returns an error if the word could not be checked
+	Valid(ctx context.Context, word string) (bool, error)
+	// WordLength returns the number of letters of the words
+	WordLength() int
+}
+
+// WordChecker decides which guesses are valid words in place of the answer provider, see Options.Checker
+type WordChecker interface {
+	// Valid reports whether word is accepted as a guess, or returns an error if the word could not be checked
+	Valid(ctx context.Context, word string) (bool, error)
+}
+
+// NumberedProvider is implemented by providers that produce numbered puzzles
+type NumberedProvider interface {
+	PuzzleNumber() int
+}
+
+// AlphabetProvider is implemented by providers whose words are not written in the Latin alphabet
+type AlphabetProvider interface {
+	Alphabet() Alphabet
+}
+
+// ResumableProvider is implemented by providers that can identify their answer and restore it in a later session
+type ResumableProvider interface {
+	AnswerID() string
+	RestoreAnswer(id string) error
+}
+
+// StaticProvider is a simple implementation of AnswerProvider that always returns the same answer
+// and accepts any guess of the right length
+type StaticProvider struct {
+	Word string
+}
+
+func (p StaticProvider) Init(ctx context.Context) error {
+	if p.Word == "" {
+		return fmt.Errorf("static answer: %w", ErrEmptyWordList)
+	}
+	return ctx.Err()
+}
+
+func (p StaticProvider) Answer() string {
+	return p.Word
+}
+
+func (p StaticProvider) Valid(_ context.Context, word string) (bool, error) {
+	return len([]rune(word)) == p.WordLength(), nil
+}
+
+func (p StaticProvider) WordLength() int {
+	return len([]rune(p.Word))
+}
+
+// DictionaryProvider is an implementation of AnswerProvider that picks a random answer from a curated
+// answer list and accepts any guess found in either the answer list or a larger allowed-guess list
+type DictionaryProvider struct {
+	answer  string
+	answers []string
+	allowed map[string]struct{} // allowed words, folded
+	length  int
+	alpha   Alphabet
+	rng     *rand.Rand // source of the random answers, nil to use the global source
+
+	freqs      []float64 // frequency of each answer, nil if unknown
+	difficulty string
+	pool       []int          // indices of the answers picked at the difficulty, nil for every answer
+	weights    []float64      // weight of each answer of the pool, nil for the same weight
+	history    *AnswerHistory // answers not to pick again, nil to allow repeats
+}
+
+func (p *DictionaryProvider) Init(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if len(p.answers) == 0 {
+		return fmt.Errorf("answers: %w", ErrEmptyWordList)
+	}
+	p.answer = p.pickAnswer()
+	return nil
+}
+
+func (p DictionaryProvider) Answer() string {
+	return p.answer
+}
+
+func (p DictionaryProvider) Valid(_ context.Context, word string) (bool, error) {
+	_, ok := p.allowed[p.alpha.Fold(word)]
+	return ok, nil
+}
+
+func (p DictionaryProvider) WordLength() int {
+	return p.length
+}
+
+// Answers returns the curated answer list
+func (p DictionaryProvider) Answers() []string {
+	return p.answers
+}
+
+// Allowed returns every word accepted as a guess, answers included, folded and in alphabetical order
+func (p DictionaryProvider) Allowed() []string {
+	words := make([]string, 0, len(p.allowed))
+	for w := range p.allowed {
+		words = append(words, w)
+	}
+	slices.Sort(words)
+	return words
+}
+
+// Alphabet returns the alphabet of the word lists
+func (p DictionaryProvider) Alphabet() Alphabet {
+	return p.alpha
+}
+
+// Clone returns a provider sharing the word lists, with its own answer and random source
+func (p *DictionaryProvider) Clone() *DictionaryProvider {
+	c := *p
+	c.answer = ""
+	c.rng = nil
+	return &c
+}
+
+// SetHistory makes the provider pick answers that are not in the history, shared with its clones.
+// The picked answers are not added to it: the caller adds them with AnswerHistory.Add once the game is over,
+// so answers picked but never played can still come up and the history never holds the answer of a game in progress.
+func (p *DictionaryProvider) SetHistory(h *AnswerHistory) {
+	p.history = h
+}
+
+// Seed makes the sequence of answers reproducible
+func (p *DictionaryProvider) Seed(seed int64) {
+	p.rng = rand.New(rand.NewSource(seed))
+}
+
+// AnswerID identifies the answer by its hash, so a save file does not spoil it
+func (p DictionaryProvider) AnswerID() string {
+	return strconv.FormatUint(wordHash(p.answer), 16)
+}
+
+// RestoreAnswer sets the answer identified by id, as returned by AnswerID
+func (p *DictionaryProvider) RestoreAnswer(id string) error {
+	for _, w := range p.answers {
+		if strconv.FormatUint(wordHash(w), 16) == id {
+			p.answer = w
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrUnknownAnswer, id)
+}
+
+// NewDictionaryProvider creates a dictionary provider from the given answer and allowed-guess lists.
+// All words must have the same length, which becomes the word length of the provider.
+// Guesses are checked in the folded form of the alphabet, so accents do not need to be typed.
+func NewDictionaryProvider(answers, allowed []string, a Alphabet) (*DictionaryProvider, error) {
+	if len(answers) == 0 {
+		return nil, fmt.Errorf("answers: %w", ErrEmptyWordList)
+	}
+	length := len([]rune(answers[0]))
+	p := &DictionaryProvider{
+		answers: answers,
+		allowed: make(map[string]struct{}, len(answers)+len(allowed)),
+		length:  length,
+		alpha:   a.orDefault(),
+	}
+	for _, w := range slices.Concat(answers, allowed) {
+		if n := len([]rune(w)); n != length {
+			return nil, fmt.Errorf("%w: %q has %d letters, expected %d", ErrMixedWordLength, w, n, length)
+		}
+		p.allowed[p.alpha.Fold(w)] = struct{}{}
+	}
+	return p, nil
+}
+
+// LoadDictionaryProvider creates a dictionary provider from word list files.
+// An empty path falls back to the corresponding built-in list for the given word length and alphabet.
+func LoadDictionaryProvider(answersPath, allowedPath string, length int, a Alphabet) (*DictionaryProvider, error) {
+	a = a.orDefault()
+	answers, freqs, err := loadWordList(answersPath, fmt.Sprintf("answers-%d.txt", length), a)
+	if err != nil {
+		return nil, fmt.Errorf("loading answers: %w", err)
+	}
+	allowed, _, err := loadWordList(allowedPath, fmt.Sprintf("allowed-%d.txt", length), a)
+	if err != nil {
+		return nil, fmt.Errorf("loading allowed guesses: %w", err)
+	}
+	p, err := NewDictionaryProvider(answers, allowed, a)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.SetFrequencies(freqs); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// loadWordList reads a word list and its frequencies from path, or the built-in list named fallback if path is empty
+func loadWordList(path, fallback string, a Alphabet) ([]string, []float64, error) {
+	var f fs.File
+	var err error
+	if path == "" {
+		f, err = defaultWordLists.Open("words/" + a.Language + "/" + fallback)
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil, fmt.Errorf("%w: %s/%s is not built in", ErrNoWordList, a.Language, fallback)
+		}
+	} else {
+		f, err = os.Open(path)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	//nolint:errcheck
+	defer f.Close()
+	words, freqs, err := ParseFrequencyList(f, a)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%s: %w", cmp.Or(path, fallback), err)
+	}
+	return words, freqs, nil
+}
+
+// ParseWordList reads one word per line, skipping blank lines and lines starting with '#'.
+// Words are lowercased and must only contain letters of the alphabet or their variants.
+// A word may be followed by its frequency, see ParseFrequencyList.
+func ParseWordList(r io.Reader, a Alphabet) ([]string, error) {
+	words, _, err := ParseFrequencyList(r, a)
+	return words, err
+}
+
+// ParseFrequencyList reads a word list like ParseWordList, with the frequency of each word, such as its number of
+// occurrences per million words, given after the word and a space. Words without a frequency get 0.
+func ParseFrequencyList(r io.Reader, a Alphabet) ([]string, []float64, error) {
+	a = a.orDefault()
+	var words []string
+	var freqs []float64
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		fields := strings.Fields(strings.ToLower(scanner.Text()))
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "#") {
+			continue
+		}
+		w := fields[0]
+		if len(fields) > 2 || strings.IndexFunc(w, func(r rune) bool { return !a.Contains(r) }) >= 0 {
+			return nil, nil, fmt.Errorf("line %d: %w: %q", line, ErrInvalidEntry, strings.TrimSpace(scanner.Text()))
+		}
+		freq := 0.0
+		if len(fields) == 2 {
+			f, err := strconv.ParseFloat(fields[1], 64)
+			if err != nil || !(f >= 0) || math.IsInf(f, 0) {
+				return nil, nil, fmt.Errorf("line %d: %w: invalid frequency %q", line, ErrInvalidEntry, fields[1])
+			}
+			freq = f
+		}
+		words = append(words, w)
+		freqs = append(freqs, freq)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+	return words, freqs, nil
+}
+
+// dailyEpoch is the date of puzzle #1
+var dailyEpoch = time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// DailyProvider is an implementation of AnswerProvider that picks the answer from the calendar date,
+// so everyone playing on the same day gets the same word
+type DailyProvider struct {
+	answer   string
+	number   int
+	order    []string // answers in a fixed, date-independent puzzle order
+	dict     *DictionaryProvider
+	location *time.Location   // timezone used to decide when a new puzzle starts
+	now      func() time.Time // current time, replaceable for deterministic puzzles
+	board    int              // board of a multi-board puzzle, starting at 0
+	boards   int              // number of boards of the puzzle
+}
+
+// puzzleNumberAt returns the number of the puzzle for the date of t in the provider's timezone, starting at 1
+func (p DailyProvider) puzzleNumberAt(t time.Time) int {
+	y, m, d := t.In(p.location).Date()
+	day := time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	return int(day.Sub(dailyEpoch).Hours()/24) + 1
+}
+
+func (p *DailyProvider) Init(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	return p.SetPuzzle(p.puzzleNumberAt(p.now()))
+}
+
+func (p DailyProvider) Answer() string {
+	return p.answer
+}
+
+func (p DailyProvider) Valid(ctx context.Context, word string) (bool, error) {
+	return p.dict.Valid(ctx, word)
+}
+
+func (p DailyProvider) WordLength() int {
+	return p.dict.WordLength()
+}
+
+// Answers returns the curated answer list of the underlying dictionary
+func (p DailyProvider) Answers() []string {
+	return p.dict.Answers()
+}
+
+// Allowed returns every word accepted as a guess, answers included, folded and in alphabetical order
+func (p DailyProvider) Allowed() []string {
+	return p.dict.Allowed()
+}
+
+// Alphabet returns the alphabet of the underlying dictionary
+func (p DailyProvider) Alphabet() Alphabet {
+	return p.dict.Alphabet()
+}
+
+func (p DailyProvider) PuzzleNumber() int {
+	return p.number
+}
+
+// AnswerID identifies the answer by its puzzle number
+func (p DailyProvider) AnswerID() string {
+	return strconv.Itoa(p.number)
+}
+
+// RestoreAnswer sets the puzzle identified by id, as returned by AnswerID,
+// so a puzzle started before the rollover can still be finished
+func (p *DailyProvider) RestoreAnswer(id string) error {
+	n, err := strconv.Atoi(id)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrUnknownAnswer, id)
+	}
+	return p.SetPuzzle(n)
+}
+
+// SetPuzzle sets the puzzle number and its answer. It returns ErrEmptyWordList if there are no answers to pick from.
+func (p *DailyProvider) SetPuzzle(n int) error {
+	if len(p.order) == 0 {
+		return fmt.Errorf("daily puzzle #%d: %w", n, ErrEmptyWordList)
+	}
+	p.number = n
+	// wrap around the list so dates before the epoch and after the last word still get a puzzle
+	// each puzzle of a multi-board game uses a run of consecutive answers, one per board
+	i := ((n-1)*p.boards + p.board) % len(p.order)
+	if i < 0 {
+		i += len(p.order)
+	}
+	p.answer = p.order[i]
+	return nil
+}
+
+// NewDailyProvider creates a daily provider on top of a dictionary provider.
+// Answers are ordered by a hash of the word so the sequence does not follow the alphabetical order of the list
+// and is the same on every machine with the same list.
+func NewDailyProvider(dict *DictionaryProvider, location *time.Location) *DailyProvider {
+	order := slices.Clone(dict.answers)
+	slices.SortFunc(order, func(a, b string) int {
+		return cmp.Or(cmp.Compare(wordHash(a), wordHash(b)), strings.Compare(a, b))
+	})
+	return &DailyProvider{
+		order:    order,
+		dict:     dict,
+		location: location,
+		now:      time.Now,
+		boards:   1,
+	}
+}
+
+// SetBoard makes the provider pick the answer of one board of a daily puzzle with the given number of boards,
+// so every board gets a different answer and the same date gives the same boards to everyone
+func (p *DailyProvider) SetBoard(board, boards int) {
+	p.board, p.boards = board, boards
+}
+
+// wordHash returns a stable 64-bit FNV-1a hash of a word
+func wordHash(w string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(w))
+	return h.Sum64()
+}
@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestDailyProviderInit(t *testing.T) {
+	newYork := time.FixedZone("UTC-5", -5*60*60)
+	auckland := time.FixedZone("UTC+13", 13*60*60)
+	tests := []struct {
+		name     string
+		location *time.Location
+		now      time.Time
+		want     int
+	}{
+		{"epoch", time.UTC, time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC), 1},
+		{"end of the first day", time.UTC, time.Date(2026, time.January, 1, 23, 59, 59, 0, time.UTC), 1},
+		{"next day", time.UTC, time.Date(2026, time.January, 2, 0, 0, 0, 0, time.UTC), 2},
+		{"next year", time.UTC, time.Date(2027, time.January, 1, 12, 0, 0, 0, time.UTC), 366},
+		{"before the epoch", time.UTC, time.Date(2025, time.December, 31, 12, 0, 0, 0, time.UTC), 0},
+		{"behind UTC before the rollover", newYork, time.Date(2026, time.January, 2, 4, 59, 0, 0, time.UTC), 1},
+		{"behind UTC after the rollover", newYork, time.Date(2026, time.January, 2, 5, 0, 0, 0, time.UTC), 2},
+		{"ahead of UTC before the rollover", auckland, time.Date(2026, time.January, 1, 10, 59, 0, 0, time.UTC), 1},
+		{"ahead of UTC after the rollover", auckland, time.Date(2026, time.January, 1, 11, 0, 0, 0, time.UTC), 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dict, err := NewDictionaryProvider([]string{"crane", "slate", "pious"}, nil, Latin)
+			if err != nil {
+				t.Fatalf("NewDictionaryProvider: %v", err)
+			}
+			p := NewDailyProvider(dict, tt.location)
+			p.now = func() time.Time { return tt.now }
+			if err := p.Init(context.Background()); err != nil {
+				t.Fatalf("Init: %v", err)
+			}
+			if got := p.PuzzleNumber(); got != tt.want {
+				t.Errorf("PuzzleNumber() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDailyProviderSetPuzzle(t *testing.T) {
+	tests := []struct {
+		name   string
+		n      int
+		board  int
+		boards int
+		want   int // index of the answer in the puzzle order
+	}{
+		{"first puzzle", 1, 0, 1, 0},
+		{"last answer", 3, 0, 1, 2},
+		{"past the end of the list", 4, 0, 1, 0},
+		{"far past the end of the list", 11, 0, 1, 1},
+		{"before the epoch", 0, 0, 1, 2},
+		{"far before the epoch", -4, 0, 1, 1},
+		{"first board", 1, 0, 2, 0},
+		{"second board", 1, 1, 2, 1},
+		{"second puzzle first board", 2, 0, 2, 2},
+		{"second puzzle second board wraps around", 2, 1, 2, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dict, err := NewDictionaryProvider([]string{"crane", "slate", "pious"}, nil, Latin)
+			if err != nil {
+				t.Fatalf("NewDictionaryProvider: %v", err)
+			}
+			p := NewDailyProvider(dict, time.UTC)
+			p.SetBoard(tt.board, tt.boards)
+			if err := p.SetPuzzle(tt.n); err != nil {
+				t.Fatalf("SetPuzzle(%d): %v", tt.n, err)
+			}
+			if got, want := p.Answer(), p.order[tt.want]; got != want {
+				t.Errorf("SetPuzzle(%d) answer = %q, want %q", tt.n, got, want)
+			}
+			// a saved game restores the same puzzle
+			r := NewDailyProvider(dict, time.UTC)
+			r.SetBoard(tt.board, tt.boards)
+			if err := r.RestoreAnswer(p.AnswerID()); err != nil || r.Answer() != p.Answer() {
+				t.Errorf("RestoreAnswer(%q) = %q, %v, want %q", p.AnswerID(), r.Answer(), err, p.Answer())
+			}
+		})
+	}
+}
+
+func TestDailyProviderOrder(t *testing.T) {
+	answers := []string{"crane", "slate", "pious"}
+	a, err := NewDictionaryProvider(answers, nil, Latin)
+	if err != nil {
+		t.Fatalf("NewDictionaryProvider: %v", err)
+	}
+	b, err := NewDictionaryProvider([]string{"pious", "crane", "slate"}, nil, Latin)
+	if err != nil {
+		t.Fatalf("NewDictionaryProvider: %v", err)
+	}
+	// the order follows the words, not their place in the list
+	pa, pb := NewDailyProvider(a, time.UTC), NewDailyProvider(b, time.UTC)
+	for n := 1; n <= len(answers); n++ {
+		if err := pa.SetPuzzle(n); err != nil {
+			t.Fatalf("SetPuzzle(%d): %v", n, err)
+		}
+		if err := pb.SetPuzzle(n); err != nil {
+			t.Fatalf("SetPuzzle(%d): %v", n, err)
+		}
+		if pa.Answer() != pb.Answer() {
+			t.Errorf("puzzle %d = %q and %q for the same words", n, pa.Answer(), pb.Answer())
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// score.go compares a guess with an answer
+package engine
+
+import "slices"
+
+// Score returns the state of each letter of guess compared with answer.
+// Letters in the correct position are Matched. Other letters are Exists as long as the answer has
+// occurrences of that letter left that are not already matched or marked, and NotMatched otherwise,
+// so a letter guessed twice is only marked twice if it appears twice in the answer.
+func Score(guess, answer string) []LetterState {
+	g, a := []rune(guess), []rune(answer)
+	result := make([]LetterState, len(g))
+	tw := newTempWord(a)
+	// first pass: check for exact matches
+	for i, r := range g {
+		result[i] = NotMatched
+		if i < len(a) && r == a[i] {
+			result[i] = Matched // mark the letter as matched
+			tw = tw.remove(r)   // remove the letter from the temporary word
+		}
+	}
+	// second pass: check for exists matches
+	// having a separate pass for exists matches allows us to not mark a letter as exists if it was already matched
+	for i, r := range g {
+		if result[i] != Matched && tw.has(r) {
+			result[i] = Exists // mark the letter as exists
+			tw = tw.remove(r)
+		}
+	}
+	return result
+}
+
+// tempWord is a slice alias for []rune that provides methods to check for existence and remove letters.
+// Used to keep track of letters that are still to be matched.
+type tempWord []rune
+
+func newTempWord(answer []rune) tempWord {
+	tw := make(tempWord, len(answer))
+	copy(tw, answer)
+	return tw
+}
+
+func (tw tempWord) has(r rune) bool {
+	return slices.Contains(tw, r)
+}
+
+func (tw tempWord) remove(r rune) tempWord {
+	for i, tr := range tw {
+		if tr == r {
+			return slices.Delete(tw, i, i+1)
+		}
+	}
+	return tw
+}
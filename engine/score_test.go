@@ -0,0 +1,39 @@
+package engine
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestScore(t *testing.T) {
+	const (
+		G = Matched
+		Y = Exists
+		B = NotMatched
+	)
+	tests := []struct {
+		name   string
+		guess  string
+		answer string
+		want   []LetterState
+	}{
+		{"all matched", "those", "those", []LetterState{G, G, G, G, G}},
+		{"none matched", "crane", "tipsy", []LetterState{B, B, B, B, B}},
+		{"anagram", "scare", "races", []LetterState{Y, Y, Y, Y, Y}},
+		{"repeated guess letter matched once", "geese", "those", []LetterState{B, B, B, G, G}},
+		{"repeated guess letter matched elsewhere", "eerie", "crane", []LetterState{B, B, Y, B, G}},
+		{"repeated guess letter with single answer letter", "speed", "abide", []LetterState{B, B, Y, B, Y}},
+		{"matched letter is not also yellow", "lolly", "hello", []LetterState{B, Y, G, G, B}},
+		{"repeated answer letter", "otter", "tower", []LetterState{Y, Y, B, G, G}},
+		{"repeated letter in both", "sassy", "assay", []LetterState{Y, Y, G, B, G}},
+		{"three of a kind", "aaabb", "bbaaa", []LetterState{Y, Y, G, Y, Y}},
+		{"unicode letters", "σοφός", "φόβος", []LetterState{B, Y, Y, Y, G}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Score(tt.guess, tt.answer); !slices.Equal(got, tt.want) {
+				t.Errorf("Score(%q, %q) = %v, want %v", tt.guess, tt.answer, got, tt.want)
+			}
+		})
+	}
+}
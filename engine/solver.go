@@ -0,0 +1,135 @@
+// solver.go plays lexis automatically by maximizing the expected information of each guess
+package engine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+)
+
+var ErrNoCandidates = errors.New("no candidate answer fits the feedback")
+
+// Solver picks guesses that maximize the expected information (entropy) over the answers
+// that are still possible given the feedback received so far.
+// It scores guesses with Score, so it follows the same rules as Game.Guess.
+type Solver struct {
+	answers    []string // every possible answer
+	guesses    []string // every word that may be guessed, answers included
+	candidates []string // answers that fit the feedback so far
+	hardMode   bool     // only guess candidates, so the revealed hints are always used
+	history    string   // guesses and feedback so far, used as the cache key
+	cache      map[string]string
+}
+
+// NewSolver creates a solver for the given answer list and allowed-guess list.
+// In hard mode only words that could still be the answer are guessed.
+// Words must be folded to the base letters of their alphabet, see Alphabet.Fold.
+func NewSolver(answers, guesses []string, hardMode bool) *Solver {
+	pool := slices.Concat(answers, guesses)
+	slices.Sort(pool)
+	s := &Solver{
+		answers:  answers,
+		guesses:  slices.Compact(pool),
+		hardMode: hardMode,
+		cache:    map[string]string{},
+	}
+	s.Reset()
+	return s
+}
+
+// Reset forgets the feedback of the current game.
+// The best guesses found so far are kept, so solving many games with the same solver gets faster.
+func (s *Solver) Reset() {
+	s.candidates = slices.Clone(s.answers)
+	s.history = ""
+}
+
+// SetOpener makes the solver start every game with the given word instead of the best one
+func (s *Solver) SetOpener(word string) {
+	s.cache[""] = word
+}
+
+// Candidates returns the answers that fit the feedback so far
+func (s *Solver) Candidates() []string {
+	return s.candidates
+}
+
+// Next returns the guess that is expected to narrow down the candidates the most,
+// preferring a candidate when several guesses are as good
+func (s *Solver) Next() string {
+	if len(s.candidates) == 0 {
+		return ""
+	}
+	if len(s.candidates) <= 2 {
+		return s.candidates[0]
+	}
+	if guess, ok := s.cache[s.history]; ok {
+		return guess
+	}
+	pool := s.guesses
+	if s.hardMode {
+		pool = s.candidates
+	}
+	isCandidate := make(map[string]bool, len(s.candidates))
+	for _, c := range s.candidates {
+		isCandidate[c] = true
+	}
+	best, bestEntropy := "", -1.0
+	counts := map[int]int{}
+	for _, guess := range pool {
+		clear(counts)
+		for _, c := range s.candidates {
+			counts[Pattern(Score(guess, c))]++
+		}
+		entropy := 0.0
+		for _, n := range counts {
+			p := float64(n) / float64(len(s.candidates))
+			entropy -= p * math.Log2(p)
+		}
+		const epsilon = 1e-9
+		if entropy > bestEntropy+epsilon || (entropy > bestEntropy-epsilon && isCandidate[guess] && !isCandidate[best]) {
+			best, bestEntropy = guess, entropy
+		}
+	}
+	s.cache[s.history] = best
+	return best
+}
+
+// Update removes the candidates that do not fit the feedback received for guess
+func (s *Solver) Update(guess string, states []LetterState) {
+	p := Pattern(states)
+	s.candidates = slices.DeleteFunc(s.candidates, func(c string) bool {
+		return Pattern(Score(guess, c)) != p
+	})
+	s.history += fmt.Sprintf("%s:%d,", guess, p)
+}
+
+// Play plays a started game until it is finished and returns the feedback of each guess.
+// The solver is reset first, so it can be reused for another game.
+func (s *Solver) Play(ctx context.Context, g *Game) ([]Feedback, error) {
+	s.Reset()
+	for g.InProgress() {
+		guess := s.Next()
+		if guess == "" {
+			return g.Guesses(), ErrNoCandidates
+		}
+		fb, err := g.Guess(ctx, guess)
+		if err != nil {
+			return g.Guesses(), fmt.Errorf("guessing %q: %w", guess, err)
+		}
+		s.Update(guess, fb.States())
+	}
+	return g.Guesses(), nil
+}
+
+// Pattern encodes the states of a scored guess as a single number, one base-3 digit per letter,
+// so that guesses can be grouped by feedback
+func Pattern(states []LetterState) int {
+	p := 0
+	for _, st := range states {
+		p = p*3 + int(st)
+	}
+	return p
+}
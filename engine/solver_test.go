@@ -0,0 +1,93 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"testing"
+)
+
+func TestPattern(t *testing.T) {
+	tests := []struct {
+		name   string
+		guess  string
+		answer string
+		want   int
+	}{
+		{"solved", "crane", "crane", 0},
+		{"no letter in the answer", "pious", "crane", 242},
+		{"letters in other positions", "react", "crane", 1*81 + 1*27 + 0*9 + 1*3 + 2},
+		{"repeated letter matched once", "eerie", "crane", 2*81 + 2*27 + 1*9 + 2*3 + 0},
+		{"repeated letter found once", "speed", "abide", 2*81 + 2*27 + 1*9 + 2*3 + 1},
+		{"repeated letter matched twice and found once", "geese", "eerie", 2*81 + 0*27 + 1*9 + 2*3 + 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Pattern(Score(tt.guess, tt.answer)); got != tt.want {
+				t.Errorf("Pattern(Score(%q, %q)) = %d, want %d", tt.guess, tt.answer, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSolverUpdate(t *testing.T) {
+	answers := []string{"crane", "slate", "plate", "crate", "trace", "pious"}
+	tests := []struct {
+		name    string
+		answer  string
+		guesses []string
+		want    []string
+	}{
+		{"no feedback", "slate", nil, answers},
+		{"one guess", "slate", []string{"crane"}, []string{"slate", "plate"}},
+		{"two guesses", "slate", []string{"crane", "plate"}, []string{"slate"}},
+		{"repeated letters", "trace", []string{"eerie"}, []string{"crane", "crate", "trace"}},
+		{"solved", "pious", []string{"pious"}, []string{"pious"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewSolver(answers, nil, false)
+			for _, g := range tt.guesses {
+				s.Update(g, Score(g, tt.answer))
+			}
+			if got := s.Candidates(); !slices.Equal(got, tt.want) {
+				t.Errorf("Candidates() = %v, want %v", got, tt.want)
+			}
+			s.Reset()
+			if got := s.Candidates(); !slices.Equal(got, answers) {
+				t.Errorf("Candidates() after Reset = %v, want %v", got, answers)
+			}
+		})
+	}
+}
+
+func TestSolverPlay(t *testing.T) {
+	answers := []string{
+		"crane", "slate", "plate", "crate", "trace", "grace", "brace", "pious", "tipsy", "those",
+		"geese", "eerie", "abide", "spare", "share", "shore", "store", "stare", "scare", "snare",
+	}
+	allowed := []string{"speed", "emcee", "lymph", "dwarf"}
+	for _, hardMode := range []bool{false, true} {
+		s := NewSolver(answers, allowed, hardMode)
+		for _, answer := range answers {
+			g := newTestGame(t, Latin, slices.Concat([]string{answer}, answers, allowed), Options{Rows: 6, HardMode: hardMode})
+			guesses, err := s.Play(context.Background(), g)
+			if err != nil {
+				t.Fatalf("Play(%q, hard mode %v): %v", answer, hardMode, err)
+			}
+			if !g.IsWon() {
+				t.Errorf("Play(%q, hard mode %v) lost after %d guesses", answer, hardMode, len(guesses))
+			}
+			if len(guesses) > g.Rows() {
+				t.Errorf("Play(%q, hard mode %v) took %d guesses, want at most %d", answer, hardMode, len(guesses), g.Rows())
+			}
+		}
+	}
+}
+
+func TestSolverOpener(t *testing.T) {
+	s := NewSolver([]string{"crane", "slate", "pious"}, []string{"lymph"}, false)
+	s.SetOpener("lymph")
+	if got := s.Next(); got != "lymph" {
+		t.Errorf("Next() = %q, want %q", got, "lymph")
+	}
+}
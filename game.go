@@ -1,212 +1,254 @@
-// game.go manages the core game logic
+// game.go connects the game engine to the grids and keyboard shown on screen
 package main
 
 import (
-	"errors"
-	"fmt"
+	"context"
 	"slices"
 
+	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/log"
+	"github.com/ieroNo47/lexis/engine"
 )
 
-const (
-	// game states
-	loading = iota
-	playing
-	won
-	lost
-)
+// boardsPerLine is the number of boards shown side by side before wrapping to a new line
+const boardsPerLine = 4
 
-var (
-	ErrInvalidWord = errors.New("invalid word")
-	ErrRowNotFull  = errors.New("row not full")
-)
+// boardGap separates the boards shown side by side
+const boardGap = "  "
 
+// game wraps the engine game with the grids and keyboard shown on screen, one grid per board.
+// The engine holds the rules and the submitted guesses, the grids also hold the letters typed in the current row.
 type game struct {
-	grid           grid
-	keyboard       keyboard
-	answerProvider answerProvider
-	answer         []rune
-	state          int
-	tempWord       tempWord
-	log            *log.Logger
-}
-
-func newGame(ap answerProvider, log *log.Logger) game {
-	grid := newGrid(6, 5)
-	grid.updateStyle(0, 0, activeStyle) // set the first cell as active
-	return game{
-		grid:           grid,
-		keyboard:       newKeyboard(),
-		answerProvider: ap,
-		answer:         []rune{},
-		state:          loading,
-		log:            log,
+	*engine.MultiGame
+	grids    []grid
+	keyboard keyboard
+	log      *log.Logger
+}
+
+// newGame creates a new game with one board per answer provider, each with a grid of opts.Rows attempts
+// of opts.WordLength letters, and a keyboard with the given rows of keys.
+// It returns an error if a provider's word length does not fit the grid.
+func newGame(providers []engine.AnswerProvider, opts engine.Options, layout []string) (game, error) {
+	eg, err := engine.NewMultiGame(providers, opts)
+	if err != nil {
+		return game{}, err
 	}
-}
-
-func (g game) isMatch() bool {
-	guess := g.grid.words[g.grid.rowIndex]
-	for i, l := range guess {
-		if l.r != g.answer[i] {
-			return false
-		}
+	// more than two boards do not fit on screen with bordered tiles
+	compact := len(providers) > 2
+	grids := make([]grid, len(providers))
+	for i := range grids {
+		grids[i] = newGrid(opts.Rows, opts.WordLength)
+		grids[i].compact = compact
 	}
-	return true
-}
-
-func (g game) isLost() bool {
-	return g.state == lost
-}
-
-func (g game) isWon() bool {
-	return g.state == won
+	return game{
+		MultiGame: eg,
+		grids:     grids,
+		keyboard:  newKeyboard(layout, len(providers)),
+		log:       opts.Logger,
+	}, nil
 }
 
-func (g game) inProgress() bool {
-	return g.state == playing
+// active returns the index of the board whose grid holds the current row: the first board that is not solved yet
+func (g game) active() int {
+	return max(0, slices.IndexFunc(g.Boards(), (*engine.Game).InProgress))
 }
 
+// rowString returns the letters typed in the current row
 func (g game) rowString() string {
+	grid := g.grids[g.active()]
 	var rowString string
-	for _, l := range g.grid.words[g.grid.rowIndex] {
+	for _, l := range grid.words[grid.rowIndex] {
 		rowString += string(l.r)
 	}
 	return rowString
 }
 
-func (g game) Answer() string {
-	return string(g.answer)
-}
-
-func (g *game) initProvider() {
-	g.log.Debug("Initializing answer provider")
-	g.answerProvider.init()
-	g.log.Debug("Answer provider initialized")
-}
-
-func (g *game) start() {
-	g.log.Debug("== Starting Game ==")
-	answer := g.answerProvider.getAnswer()
-	g.answer = []rune(answer)
-	g.log.Debug("Answer", "answer", string(g.answer))
-	g.state = playing
+// hasProgress reports whether the game is in progress and at least one letter has been entered or a hint used
+func (g game) hasProgress() bool {
+	grid := g.grids[g.active()]
+	return g.InProgress() && (len(g.Words()) > 0 || grid.words[grid.rowIndex][0].r != ' ' || len(g.Hints()) > 0)
 }
 
+// processLetter types a letter in the current row of every board that is not solved yet
 func (g *game) processLetter(text string) {
-	if g.inProgress() && len(text) > 0 {
-		g.grid.setLetter([]rune(text)[0])
+	if !g.InProgress() || len(text) == 0 {
+		return
+	}
+	for i, b := range g.Boards() {
+		if b.InProgress() {
+			g.grids[i].setLetter([]rune(text)[0])
+		}
 	}
 }
 
+// processDelete deletes the last letter of the current row of every board that is not solved yet
 func (g *game) processDelete() {
-	if g.inProgress() {
-		g.grid.deleteLetter()
+	if !g.InProgress() {
+		return
+	}
+	for i, b := range g.Boards() {
+		if b.InProgress() {
+			g.grids[i].deleteLetter()
+		}
 	}
 }
 
 // rowReady checks if the current row is ready to be submitted and returns an error if it is not
 // may perform I/O operations such as validating the word with the answer provider, so it should be called in a separate goroutine to avoid blocking the UI
-func (g *game) rowReady() error {
-	if !g.grid.rowFull() {
+func (g *game) rowReady(ctx context.Context) error {
+	if !g.grids[g.active()].rowFull() {
 		g.log.Info("Row is not full, cannot submit")
-		return fmt.Errorf("cannot submit: %w", ErrRowNotFull)
-	}
-	if !g.answerProvider.validWord(g.rowString()) {
-		g.log.Info("Invalid word submitted", "word", g.rowString())
-		return fmt.Errorf("cannot submit: %w", ErrInvalidWord)
+		return engine.ErrRowNotFull
 	}
-	return nil
+	return g.Validate(ctx, g.rowString())
 }
 
-// Submit processes current row and updates letter states based on the answer.
-// It also updates the game state to won or lost if applicable.
-func (g *game) Submit() {
-	if g.isMatch() {
-		g.log.Info("Match found")
-		g.state = won // mark the game as won
-		g.log.Debug("Marking game as finished.", "reason", "win")
-		for i, l := range g.grid.words[g.grid.rowIndex] {
-			g.grid.updateState(g.grid.rowIndex, i, matched)
-			g.keyboard.updateLetterState(l.r, matched) // update the keyboard state
-		}
-		return
+// Submit submits the current row to the engine and updates the letter states on the grids and keyboard.
+// Boards that are still in progress move to the next row, solved boards stay frozen.
+// The row must have been checked with rowReady first, it is not checked with the answer provider again.
+func (g *game) Submit() error {
+	fbs, err := g.Apply(g.rowString())
+	if err != nil {
+		g.log.Error("Guess rejected", "err", err)
+		return err
 	}
-	tw := newTempWord(g.answer)
-	// first pass: check for exact matches
-	g.log.Debug("== First Pass: Exact Matches ==")
-	for i, l := range g.grid.words[g.grid.rowIndex] {
-		// change style based on match
-		if l.r == g.answer[i] {
-			g.log.Debug("Update", "letter", string(l.r), "index", i, "state", states[matched])
-			g.grid.updateState(g.grid.rowIndex, i, matched) // mark the letter as matched
-			g.keyboard.updateLetterState(l.r, matched)      // update the keyboard state
-			tw = tw.remove(l.r)                             // remove the letter from the temporary word
+	for i, fb := range fbs {
+		if fb == nil {
+			continue
 		}
-	}
-	// 	// second pass: check for exists matches and not matches
-	// 	// having a separate pass for exists matches allows us to not mark a letter as exists if it was already matched
-	var targetState = notMatched
-	g.log.Debug("== Second Pass: Exists and Not Matches ==")
-	for i, l := range g.grid.words[g.grid.rowIndex] {
-		if tw.has(l.r) && l.state != matched {
-			targetState = exists
-			g.log.Debug("Row Update", "letter", string(l.r), "index", i, "state", states[exists])
-			g.grid.updateState(g.grid.rowIndex, i, exists) // mark the letter as exists
-			tw = tw.remove(l.r)                            // remove the letter from the temporary word
-		} else if l.state != matched {
-			g.log.Debug("Row Update", "letter", string(l.r), "index", i, "state", "notMatched")
-			g.grid.updateState(g.grid.rowIndex, i, notMatched) // mark the letter as not matched
+		g.showFeedback(i, g.grids[i].rowIndex, fb)
+		if g.Boards()[i].InProgress() && g.grids[i].goToNextRow() {
+			g.log.Info("Moving to next row", "board", i+1)
 		}
-		if g.keyboard.getLetterState(l.r) != matched { // only update the keyboard state if it is not already matched
-			g.log.Debug("Keyboard Update", "letter", string(l.r), "to", states[targetState], "from", states[g.keyboard.getLetterState(l.r)])
-			g.keyboard.updateLetterState(l.r, targetState)
-		}
-		targetState = notMatched // reset target state
 	}
-	if g.grid.goToNextRow() {
-		g.log.Info("Moving to next row")
-		g.tempWord = make(tempWord, len(g.answer))
-	} else {
-		g.state = lost // mark the game as lost if there are no more rows
-		g.log.Debug("Marking game as finished.", "reason", "no more rows")
-		// TODO: return as custom error to handle in model?
+	return nil
+}
+
+// showFeedback shows the feedback of a guess on a grid row of a board and on the keyboard
+func (g *game) showFeedback(board, row int, fb engine.Feedback) {
+	for i, l := range fb {
+		g.grids[board].words[row][i].r = l.Rune
+		g.grids[board].updateState(row, i, l.State)
+		// keys show the base letters, accents and final forms are the same key
+		g.keyboard.updateLetterState(board, g.Alphabet().FoldRune(l.Rune), g.Boards()[board].KeyState(l.Rune))
+	}
+}
+
+// hint uses a hint and shows a revealed letter on the grid
+func (g *game) hint(kind engine.HintKind) (engine.Hint, error) {
+	h, err := g.Hint(kind)
+	if err != nil {
+		return h, err
+	}
+	g.showHint(h)
+	return h, nil
+}
+
+// showHint shows a revealed letter in its column of the active row of its board
+func (g *game) showHint(h engine.Hint) {
+	if h.Kind == engine.HintLetter {
+		g.grids[h.Board].reveal(h.Position-1, h.Letter)
 	}
 }
 
+// reset starts the game again with the same answer, showing the letters revealed by the hints kept
 func (g *game) reset() {
-	g.grid.reset()
+	g.Reset()
+	for i := range g.grids {
+		g.grids[i].reset()
+	}
 	g.keyboard.reset()
-	g.state = playing
+	for _, h := range g.Hints() {
+		g.showHint(h)
+	}
 }
 
-func (g game) debugState() (int, int, string) {
-	var currentRow string
-	for _, l := range g.grid.words[g.grid.rowIndex] {
-		currentRow += string(l.r)
+// snapshot returns the game state to be saved, or false if the answer provider cannot restore its answer
+func (g game) snapshot() (savedGame, bool) {
+	s, ok := g.Snapshot()
+	if !ok {
+		return savedGame{}, false
 	}
-	return g.grid.rowIndex, g.grid.colIndex, currentRow
+	return savedGame{Snapshot: s, Input: g.rowString()}, true
 }
 
-// tempWord is a slice alias for []rune that provides methods to check for existence and remove letters.
-// Used to keep track of letters that are still to be matched.
-type tempWord []rune
+// canRestore reports whether a saved game fits this game's grid, mode and answer provider
+func (g game) canRestore(s savedGame) bool {
+	return g.CanRestore(s.Snapshot) && len([]rune(s.Input)) == g.WordLength()
+}
 
-func newTempWord(answer []rune) tempWord {
-	tw := make(tempWord, len(answer))
-	copy(tw, answer)
-	return tw
+// restore replaces the current game with a saved one, including the letters typed in the current row
+func (g *game) restore(s savedGame) error {
+	if err := g.Restore(s.Snapshot); err != nil {
+		return err
+	}
+	for i := range g.grids {
+		g.grids[i].reset()
+	}
+	g.keyboard.reset()
+	for i, b := range g.Boards() {
+		guesses := b.Guesses()
+		for row, fb := range guesses {
+			g.showFeedback(i, row, fb)
+			// a solved board stays on the row of its last guess
+			if row < len(guesses)-1 || b.InProgress() {
+				g.grids[i].goToNextRow()
+			}
+		}
+	}
+	for _, h := range g.Hints() {
+		g.showHint(h)
+	}
+	for _, r := range s.Input {
+		if r != ' ' {
+			g.processLetter(string(r))
+		}
+	}
+	return nil
 }
 
-func (tw tempWord) has(r rune) bool {
-	return slices.Contains(tw, r)
+// render renders the boards side by side, wrapping to a new line every boardsPerLine boards,
+// at the current frame of an animation
+func (g game) render(a animation) string {
+	var lines, boards []string
+	gap := boardGap
+	if a.kind == animationShake {
+		gap = "" // shaking grids are padded by half the gap on each side
+	}
+	for i := range g.grids {
+		if len(boards) > 0 {
+			boards = append(boards, gap)
+		}
+		boards = append(boards, g.grids[i].render(a, g.animatedRow(i, a)))
+		if (i+1)%boardsPerLine == 0 || i == len(g.grids)-1 {
+			if len(lines) > 0 {
+				lines = append(lines, "") // gap between lines of boards
+			}
+			lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top, boards...))
+			boards = nil
+		}
+	}
+	return lipgloss.JoinVertical(lipgloss.Center, lines...)
 }
 
-func (tw tempWord) remove(r rune) tempWord {
-	for i, tr := range tw {
-		if tr == r {
-			return slices.Delete(tw, i, i+1)
+// animatedRow returns the row of a board played by an animation, -1 if the board is not animated:
+// the last guess for a flip, if the board scored it, and the current row of a board in progress for a shake
+func (g game) animatedRow(board int, a animation) int {
+	b := g.Boards()[board]
+	switch a.kind {
+	case animationFlip:
+		if n := len(b.Guesses()); n > 0 && n == len(g.Words()) {
+			return n - 1
+		}
+	case animationShake:
+		if b.InProgress() {
+			return g.grids[board].rowIndex
 		}
 	}
-	return tw
+	return -1
+}
+
+func (g game) debugState() (int, int, string) {
+	grid := g.grids[g.active()]
+	return grid.rowIndex, grid.colIndex, g.rowString()
 }
@@ -1,38 +1,15 @@
 // grid.go provides the grid data structure and logic for managing letter states and styles
 package main
 
-import "charm.land/lipgloss/v2"
-
-// consts for states of a letter, matched, exists, not matched
-// currently helps when checking the letter state over different iterations
-const (
-	matched    = iota // letter is in the correct position
-	exists            // letter is in the word but not in the correct position
-	notMatched        // letter is not in the word
-	notChecked        // letter has not been checked yet
+import (
+	"charm.land/lipgloss/v2"
+	"github.com/ieroNo47/lexis/engine"
 )
 
-// map to get string representation of each state
-var states = map[int]string{
-	matched:    "matched",
-	exists:     "exists",
-	notMatched: "notMatched",
-	notChecked: "notChecked",
-}
-
-// match a state to a style
-var stateStyles = map[int]lipgloss.Style{
-	matched:    exactMatchStyle,
-	exists:     existsMatchStyle,
-	notMatched: notMatchStyle,
-	notChecked: defaultStyle,
-}
-
-// letter represents a single letter and its style
+// letter represents a single letter and its state, which decides its style when rendered
 type letter struct {
 	r     rune
-	style lipgloss.Style
-	state int
+	state engine.LetterState
 }
 
 // word represents a row of letters
@@ -43,6 +20,8 @@ type grid struct {
 	words    []word
 	colIndex int
 	rowIndex int
+	revealed []rune // letters revealed by hints in each column, 0 if none
+	compact  bool   // render one line per row without borders, to fit several boards on screen
 }
 
 // Initializes a new grid with the specified number of rows and columns
@@ -51,11 +30,12 @@ func newGrid(rows, cols int) grid {
 		words:    make([]word, rows),
 		colIndex: 0,
 		rowIndex: 0,
+		revealed: make([]rune, cols),
 	}
 	for i := range grid.words {
 		grid.words[i] = make([]letter, cols)
 		for j := range grid.words[i] {
-			grid.words[i][j] = letter{r: ' ', style: stateStyles[notChecked], state: notChecked}
+			grid.words[i][j] = letter{r: ' ', state: engine.NotChecked}
 		}
 	}
 	return grid
@@ -74,7 +54,6 @@ func (g *grid) setLetter(r rune) bool {
 		// move to the next column if we're not at the end of the row
 		if g.colIndex < len(g.words[g.rowIndex])-1 {
 			g.colIndex++ // move to the next column
-			g.updateActiveCell()
 		}
 	}
 	return set
@@ -86,7 +65,6 @@ func (g *grid) deleteLetter() {
 	if g.colIndex > 0 {
 		if g.words[g.rowIndex][g.colIndex].r == ' ' {
 			g.colIndex--
-			g.updateActiveCell()
 		}
 	}
 	g.words[g.rowIndex][g.colIndex].r = ' ' // delete the letter
@@ -102,64 +80,109 @@ func (g *grid) goToNextRow() bool {
 	if g.rowIndex < len(g.words)-1 {
 		g.rowIndex++   // move to the next row
 		g.colIndex = 0 // reset column index
-		g.updateActiveCell()
 		return true
 	} else {
 		return false // no more rows
 	}
 }
 
-// updateStyle updates the style of a letter at a specific position in the grid
-func (g *grid) updateStyle(row, col int, style lipgloss.Style) {
+// updateState updates the state of a letter at a specific position in the grid
+func (g *grid) updateState(row, col int, state engine.LetterState) {
 	if row < len(g.words) && col < len(g.words[row]) {
-		g.words[row][col].style = style
+		g.words[row][col].state = state
 	}
 }
 
-// updateActiveCell updates the style of the active cell in the current row
-func (g *grid) updateActiveCell() {
-	for i := range g.words[g.rowIndex] {
-		if i == g.colIndex {
-			g.updateStyle(g.rowIndex, i, activeStyle) // set the active style for the current cell
-		} else {
-			g.updateStyle(g.rowIndex, i, defaultStyle) // reset style for other cells in the active row
-		}
-	}
-}
-
-// updateState updates the state of a letter at a specific position in the grid
-func (g *grid) updateState(row, col int, state int) {
-	if row < len(g.words) && col < len(g.words[row]) {
-		style, exists := stateStyles[state]
-		if exists {
-			g.words[row][col].style = style // update the style based on the state
-			g.words[row][col].state = state // update the state of the letter
-		}
+// style returns the style of a tile: the active style for the cell being typed, the style of its state otherwise.
+// Styles are looked up when rendering so a change of theme applies to the tiles already shown.
+func (g *grid) style(row, col int) lipgloss.Style {
+	l := g.words[row][col]
+	if l.state == engine.NotChecked && row == g.rowIndex && col == g.colIndex {
+		return activeStyle
 	}
+	return stateStyles[l.state]
 }
 
 // reset resets the grid to its initial state
 func (g *grid) reset() {
 	for i := range g.words {
 		for j := range g.words[i] {
-			g.words[i][j] = letter{r: ' ', style: stateStyles[notChecked], state: notChecked}
+			g.words[i][j] = letter{r: ' ', state: engine.NotChecked}
 		}
 	}
+	clear(g.revealed)
 	g.colIndex = 0
 	g.rowIndex = 0
-	g.updateActiveCell() // reset the active cell style
 }
 
-// render renders the grid as a string, with each letter styled according to its state
-func (g *grid) render() string {
+// reveal shows a letter revealed by a hint in the empty tiles of its column in the active row
+func (g *grid) reveal(col int, r rune) {
+	if col < len(g.revealed) {
+		g.revealed[col] = r
+	}
+}
+
+// render renders the grid as a string, with each letter styled according to its state,
+// at the current frame of the animation of the given row
+func (g *grid) render(a animation, row int) string {
+	if g.compact {
+		return g.renderCompact(a, row)
+	}
 	rows := make([]string, 0, len(g.words))
-	for _, w := range g.words {
+	for i, w := range g.words {
 		letters := []string{}
-		for _, l := range w {
+		for j, l := range w {
+			// show the revealed letters in the empty tiles of the active row, keeping the active cell border
+			if i == g.rowIndex && l.r == ' ' && g.revealed[j] != 0 {
+				style := stateStyles[engine.Revealed]
+				if j == g.colIndex {
+					style = style.BorderForeground(activeStyle.GetBorderTopForeground())
+				}
+				letters = append(letters, style.Render(string(g.revealed[j])))
+				continue
+			}
 			// render each letter with its style
-			letters = append(letters, l.style.Render(string(l.r)))
+			style, text := g.style(i, j), string(l.r)
+			if a.kind == animationFlip && i == row {
+				style, text = a.flipTile(j, style, defaultStyle, text)
+			}
+			letters = append(letters, style.Render(text))
+		}
+		rows = append(rows, g.renderRow(a, i == row, letters))
+	}
+	return lipgloss.JoinVertical(lipgloss.Center, rows...)
+}
+
+// renderRow joins the rendered letters of a row, padded to its offset when the grid is shaking
+func (g *grid) renderRow(a animation, animated bool, letters []string) string {
+	line := lipgloss.JoinHorizontal(lipgloss.Center, letters...)
+	if a.kind == animationShake {
+		return a.shakeRow(line, animated)
+	}
+	return line
+}
+
+// renderCompact renders the grid with one line per row, showing the letter states as background colors
+func (g *grid) renderCompact(a animation, row int) string {
+	rows := make([]string, 0, len(g.words))
+	for i, w := range g.words {
+		letters := make([]string, len(w))
+		for j, l := range w {
+			r, state := l.r, l.state
+			if i == g.rowIndex && r == ' ' && g.revealed[j] != 0 {
+				r, state = g.revealed[j], engine.Revealed
+			}
+			style := compactStyles[state]
+			if l.state == engine.NotChecked && i == g.rowIndex && j == g.colIndex {
+				style = compactActiveStyle
+			}
+			text := string(r)
+			if a.kind == animationFlip && i == row {
+				style, text = a.flipTile(j, style, compactDefaultStyle, text)
+			}
+			letters[j] = style.Render(text)
 		}
-		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Center, letters...))
+		rows = append(rows, g.renderRow(a, i == row, letters))
 	}
 	return lipgloss.JoinVertical(lipgloss.Center, rows...)
 }
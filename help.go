@@ -2,31 +2,45 @@
 package main
 
 import (
+	"fmt"
+	"slices"
+
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/key"
+	"github.com/ieroNo47/lexis/engine"
 )
 
 type keyMap struct {
-	Letter  key.Binding
-	Delete  key.Binding
-	Submit  key.Binding
-	Restart key.Binding
-	Quit    key.Binding
+	Letter   key.Binding
+	Delete   key.Binding
+	Submit   key.Binding
+	HardMode key.Binding
+	Stats    key.Binding
+	Hint     key.Binding
+	Theme    key.Binding
+	Restart  key.Binding
+	Quit     key.Binding
+	// bindings used by prompts, not shown in the help bar
+	Confirm key.Binding
+	Decline key.Binding
+	Retry   key.Binding
+	Share   key.Binding
+	// hint menu choices
+	HintLetter key.Binding
+	HintCount  key.Binding
+	HintAbsent key.Binding
 }
 
 func (k keyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Letter, k.Delete, k.Submit, k.Restart, k.Quit}
+	return []key.Binding{k.Letter, k.Delete, k.Submit, k.HardMode, k.Stats, k.Hint, k.Theme, k.Restart, k.Quit}
 }
 
 func (k keyMap) FullHelp() [][]key.Binding {
-	return [][]key.Binding{{k.Letter, k.Delete, k.Submit, k.Restart, k.Quit}}
+	return [][]key.Binding{{k.Letter, k.Delete, k.Submit, k.HardMode, k.Stats, k.Hint, k.Theme, k.Restart, k.Quit}}
 }
 
 var keys = keyMap{
-	Letter: key.NewBinding(
-		key.WithKeys("a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q", "r", "s", "t", "u", "v", "w", "x", "y", "z"),
-		key.WithHelp("a-z", "Set Letter"),
-	),
+	Letter: letterKeys(engine.Latin),
 	Delete: key.NewBinding(
 		key.WithKeys("backspace", "delete"),
 		key.WithHelp("backspace", "Delete Letter"),
@@ -35,6 +49,22 @@ var keys = keyMap{
 		key.WithKeys("enter"),
 		key.WithHelp("enter", "Submit"),
 	),
+	HardMode: key.NewBinding(
+		key.WithKeys("ctrl+t"),
+		key.WithHelp("ctrl+t", "Hard Mode"),
+	),
+	Stats: key.NewBinding(
+		key.WithKeys("ctrl+s"),
+		key.WithHelp("ctrl+s", "Stats"),
+	),
+	Hint: key.NewBinding(
+		key.WithKeys("ctrl+g"),
+		key.WithHelp("ctrl+g", "Hint"),
+	),
+	Theme: key.NewBinding(
+		key.WithKeys("ctrl+o"),
+		key.WithHelp("ctrl+o", "Theme"),
+	),
 	Restart: key.NewBinding(
 		key.WithKeys("ctrl+r"),
 		key.WithHelp("ctrl+r", "Restart"),
@@ -43,6 +73,48 @@ var keys = keyMap{
 		key.WithKeys("ctrl+c", "esc"),
 		key.WithHelp("ctrl+c/esc", "Quit"),
 	),
+	Confirm: key.NewBinding(
+		key.WithKeys("y", "enter"),
+		key.WithHelp("y", "Yes"),
+	),
+	Decline: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "No"),
+	),
+	Retry: key.NewBinding(
+		key.WithKeys("r", "enter"),
+		key.WithHelp("r", "Retry"),
+	),
+	Share: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "Copy Result"),
+	),
+	HintLetter: key.NewBinding(
+		key.WithKeys("1"),
+		key.WithHelp("1", "Reveal a letter"),
+	),
+	HintCount: key.NewBinding(
+		key.WithKeys("2"),
+		key.WithHelp("2", "Count possible answers"),
+	),
+	HintAbsent: key.NewBinding(
+		key.WithKeys("3"),
+		key.WithHelp("3", "Name an absent letter"),
+	),
+}
+
+// letterKeys returns the binding of the letter keys of an alphabet, base letters and their variants
+func letterKeys(a engine.Alphabet) key.Binding {
+	runes := a.Runes()
+	letters := make([]string, len(runes))
+	for i, r := range runes {
+		letters[i] = string(r)
+	}
+	first, last := slices.Min(a.Letters), slices.Max(a.Letters)
+	return key.NewBinding(
+		key.WithKeys(letters...),
+		key.WithHelp(fmt.Sprintf("%c-%c", first, last), "Set Letter"),
+	)
 }
 
 func newHelp() help.Model {
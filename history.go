@@ -0,0 +1,143 @@
+// history.go keeps the answers each profile has played in random games, so they do not repeat across sessions
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ieroNo47/lexis/engine"
+)
+
+// answerHistory holds the answers played in the current round of every profile and word list,
+// see engine.AnswerHistory
+type answerHistory struct {
+	Profiles map[string]map[string][]string `json:"profiles"` // played answers by profile, then by word list
+	path     string                         // file the history is loaded from and saved to
+}
+
+// rotation keeps the answers of the random games of a profile from repeating
+type rotation struct {
+	answers *engine.AnswerHistory // answers played, shared by the answer providers
+	history answerHistory
+	profile string
+	list    string // word list of the answers, see wordListKey
+}
+
+// historySavedMsg is a message that is sent when the history file has been written, with the error if it failed
+type historySavedMsg struct{ err error }
+
+// defaultHistoryPath returns the path of the history file in the user's data directory
+func defaultHistoryPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "history.json"), nil
+}
+
+// loadHistory reads the history file at path. A missing file is not an error and returns an empty history.
+func loadHistory(path string) (answerHistory, error) {
+	h := answerHistory{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return h, err
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, fmt.Errorf("%s: %w", path, err)
+	}
+	return h, nil
+}
+
+// save writes the history file, replacing the previous one only once the new one is fully written
+func (h answerHistory) save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
+
+// played returns the answers a profile has played from a word list
+func (h answerHistory) played(profile, list string) []string {
+	return h.Profiles[profile][list]
+}
+
+// lists returns the word lists a profile has played, in alphabetical order
+func (h answerHistory) lists(profile string) []string {
+	return slices.Sorted(maps.Keys(h.Profiles[profile]))
+}
+
+// set replaces the answers a profile has played from a word list
+func (h *answerHistory) set(profile, list string, played []string) {
+	if h.Profiles == nil {
+		h.Profiles = map[string]map[string][]string{}
+	}
+	if h.Profiles[profile] == nil {
+		h.Profiles[profile] = map[string][]string{}
+	}
+	h.Profiles[profile][list] = played
+}
+
+// clear forgets the answers a profile has played
+func (h *answerHistory) clear(profile string) {
+	delete(h.Profiles, profile)
+}
+
+// wordListKey identifies the answer list in the history by its language and word length,
+// so the history still applies when words are added to the list
+func wordListKey(cfg config) string {
+	return fmt.Sprintf("%s/%d", cfg.Language, cfg.WordLength)
+}
+
+// newRotation loads the history of the profile and makes the random answer providers skip the answers it has played.
+// It returns nil for the providers whose answers are not random or are replayed from a seed.
+func newRotation(cfg config, providers []engine.AnswerProvider) (*rotation, error) {
+	if cfg.Provider != providerRandom || cfg.Seed != 0 {
+		return nil, nil
+	}
+	h, err := loadHistory(cfg.HistoryFile)
+	if err != nil {
+		return nil, err
+	}
+	r := &rotation{history: h, profile: cfg.Profile, list: wordListKey(cfg)}
+	r.answers = engine.NewAnswerHistory(h.played(r.profile, r.list))
+	for _, p := range providers {
+		if dp, ok := p.(*engine.DictionaryProvider); ok {
+			dp.SetHistory(r.answers)
+		}
+	}
+	return r, nil
+}
+
+// record adds the answers of a finished game to the history and returns a command that saves it
+func (r *rotation) record(answers []string) tea.Cmd {
+	r.answers.Add(answers...)
+	h := answerHistory{path: r.history.path}
+	// the history is copied, so it is not changed while it is saved
+	for profile, lists := range r.history.Profiles {
+		for list, played := range lists {
+			h.set(profile, list, played)
+		}
+	}
+	h.set(r.profile, r.list, r.answers.Played())
+	r.history = h
+	return func() tea.Msg {
+		return historySavedMsg{err: h.save()}
+	}
+}
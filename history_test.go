@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/log"
+	"github.com/ieroNo47/lexis/engine"
+)
+
+func TestRecordAnswersOnFinish(t *testing.T) {
+	p, err := engine.NewDictionaryProvider([]string{"crane", "slate"}, nil, engine.Latin)
+	if err != nil {
+		t.Fatalf("NewDictionaryProvider: %v", err)
+	}
+	dir := t.TempDir()
+	cfg := defaultConfig()
+	cfg.HistoryFile = filepath.Join(dir, "history.json")
+	cfg.SaveFile = filepath.Join(dir, "save.json")
+	providers := []engine.AnswerProvider{p}
+	rot, err := newRotation(cfg, providers)
+	if err != nil {
+		t.Fatalf("newRotation: %v", err)
+	}
+	m, err := newModel(log.New(io.Discard), providers, p, cfg, stats{path: filepath.Join(dir, "stats.json")}, nil, rot)
+	if err != nil {
+		t.Fatalf("newModel: %v", err)
+	}
+	if err := m.game.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	m = update(m, initCompleteMsg(true))
+	if played := rot.answers.Played(); len(played) != 0 {
+		t.Errorf("history after the start = %v, want empty", played)
+	}
+	answer := m.game.Answers()[0]
+	for _, r := range answer {
+		m = update(m, tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	m = update(m, validWordMsg(true))
+	if !m.game.IsFinished() {
+		t.Fatalf("game not finished after guessing %q", answer)
+	}
+	if played, want := rot.answers.Played(), []string{answer}; !slices.Equal(played, want) {
+		t.Errorf("history after the finish = %v, want %v", played, want)
+	}
+}
+
+// update sends msg to the model and returns the updated model, without running the returned command
+func update(m model, msg tea.Msg) model {
+	next, _ := m.Update(msg)
+	return next.(model)
+}
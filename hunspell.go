@@ -0,0 +1,60 @@
+// hunspell.go finds and loads the Hunspell dictionaries installed on the system to check the guesses
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ieroNo47/lexis/engine"
+)
+
+// hunspellDirs are the directories searched for a dictionary given by name, after the ones in $DICPATH
+var hunspellDirs = []string{
+	"/usr/share/hunspell",
+	"/usr/share/myspell",
+	"/usr/share/myspell/dicts",
+	"/usr/local/share/hunspell",
+	"/Library/Spelling",
+}
+
+var ErrNoDictionary = errors.New("hunspell dictionary not found")
+
+// hunspellFiles returns the paths of the .dic and .aff files of a Hunspell dictionary,
+// given by name such as en_US or by the path of its .dic file
+func hunspellFiles(dict string) (dic, aff string, err error) {
+	if strings.HasSuffix(dict, ".dic") || strings.ContainsRune(dict, filepath.Separator) {
+		base := strings.TrimSuffix(dict, ".dic")
+		return base + ".dic", base + ".aff", nil
+	}
+	dirs := append(filepath.SplitList(os.Getenv("DICPATH")), hunspellDirs...)
+	for _, dir := range dirs {
+		base := filepath.Join(dir, dict)
+		if _, err := os.Stat(base + ".dic"); err == nil {
+			return base + ".dic", base + ".aff", nil
+		}
+	}
+	return "", "", fmt.Errorf("%w: no %s.dic in %v", ErrNoDictionary, dict, dirs)
+}
+
+// loadChecker loads the Hunspell dictionary selected in the config, or returns nil if the answer provider
+// checks the guesses
+func loadChecker(cfg config) (engine.WordChecker, error) {
+	if cfg.Hunspell == "" {
+		return nil, nil
+	}
+	dic, aff, err := hunspellFiles(cfg.Hunspell)
+	if err != nil {
+		return nil, err
+	}
+	h, err := engine.LoadHunspell(dic, aff, cfg.WordLength, cfg.alphabet())
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dic, err)
+	}
+	if h.Len() == 0 {
+		return nil, fmt.Errorf("%s: %w: no %d-letter words", dic, engine.ErrEmptyWordList, cfg.WordLength)
+	}
+	return h, nil
+}
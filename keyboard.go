@@ -2,7 +2,12 @@
 package main
 
 import (
+	"maps"
+	"slices"
+	"strings"
+
 	"charm.land/lipgloss/v2"
+	"github.com/ieroNo47/lexis/engine"
 )
 
 type position struct {
@@ -13,103 +18,219 @@ type position struct {
 type keyboardLetter struct {
 	position position
 	letter   letter
+	states   []engine.LetterState // state of the letter on each board of a multi-board game
 }
 
 type keyboard struct {
 	letters map[rune]keyboardLetter // map of letters to their positions and styles
-	layout  [3][]keyboardLetter     // keyboard layout with 3 rows
-}
-
-// qwertyuiop
-// asdfghjkl
-// zxcvbnm
-var letters = map[rune]keyboardLetter{
-	// Row 0: qwertyuiop
-	'q': {position: position{row: 0, column: 0}, letter: letter{r: 'q', style: stateStyles[notChecked], state: notChecked}},
-	'w': {position: position{row: 0, column: 1}, letter: letter{r: 'w', style: stateStyles[notChecked], state: notChecked}},
-	'e': {position: position{row: 0, column: 2}, letter: letter{r: 'e', style: stateStyles[notChecked], state: notChecked}},
-	'r': {position: position{row: 0, column: 3}, letter: letter{r: 'r', style: stateStyles[notChecked], state: notChecked}},
-	't': {position: position{row: 0, column: 4}, letter: letter{r: 't', style: stateStyles[notChecked], state: notChecked}},
-	'y': {position: position{row: 0, column: 5}, letter: letter{r: 'y', style: stateStyles[notChecked], state: notChecked}},
-	'u': {position: position{row: 0, column: 6}, letter: letter{r: 'u', style: stateStyles[notChecked], state: notChecked}},
-	'i': {position: position{row: 0, column: 7}, letter: letter{r: 'i', style: stateStyles[notChecked], state: notChecked}},
-	'o': {position: position{row: 0, column: 8}, letter: letter{r: 'o', style: stateStyles[notChecked], state: notChecked}},
-	'p': {position: position{row: 0, column: 9}, letter: letter{r: 'p', style: stateStyles[notChecked], state: notChecked}},
-
-	// Row 1: asdfghjkl
-	'a': {position: position{row: 1, column: 0}, letter: letter{r: 'a', style: stateStyles[notChecked], state: notChecked}},
-	's': {position: position{row: 1, column: 1}, letter: letter{r: 's', style: stateStyles[notChecked], state: notChecked}},
-	'd': {position: position{row: 1, column: 2}, letter: letter{r: 'd', style: stateStyles[notChecked], state: notChecked}},
-	'f': {position: position{row: 1, column: 3}, letter: letter{r: 'f', style: stateStyles[notChecked], state: notChecked}},
-	'g': {position: position{row: 1, column: 4}, letter: letter{r: 'g', style: stateStyles[notChecked], state: notChecked}},
-	'h': {position: position{row: 1, column: 5}, letter: letter{r: 'h', style: stateStyles[notChecked], state: notChecked}},
-	'j': {position: position{row: 1, column: 6}, letter: letter{r: 'j', style: stateStyles[notChecked], state: notChecked}},
-	'k': {position: position{row: 1, column: 7}, letter: letter{r: 'k', style: stateStyles[notChecked], state: notChecked}},
-	'l': {position: position{row: 1, column: 8}, letter: letter{r: 'l', style: stateStyles[notChecked], state: notChecked}},
-
-	// Row 2: zxcvbnm
-	'z': {position: position{row: 2, column: 0}, letter: letter{r: 'z', style: stateStyles[notChecked], state: notChecked}},
-	'x': {position: position{row: 2, column: 1}, letter: letter{r: 'x', style: stateStyles[notChecked], state: notChecked}},
-	'c': {position: position{row: 2, column: 2}, letter: letter{r: 'c', style: stateStyles[notChecked], state: notChecked}},
-	'v': {position: position{row: 2, column: 3}, letter: letter{r: 'v', style: stateStyles[notChecked], state: notChecked}},
-	'b': {position: position{row: 2, column: 4}, letter: letter{r: 'b', style: stateStyles[notChecked], state: notChecked}},
-	'n': {position: position{row: 2, column: 5}, letter: letter{r: 'n', style: stateStyles[notChecked], state: notChecked}},
-	'm': {position: position{row: 2, column: 6}, letter: letter{r: 'm', style: stateStyles[notChecked], state: notChecked}},
-}
-
-func newKeyboard() keyboard {
-	// Initialize the keyboard with the letters and their positions
-	layout := [3][]keyboardLetter{}
-	layout[0] = make([]keyboardLetter, 10)
-	layout[1] = make([]keyboardLetter, 9)
-	layout[2] = make([]keyboardLetter, 7)
-	for _, kl := range letters {
-		layout[kl.position.row][kl.position.column] = kl
+	layout  [][]keyboardLetter      // keyboard layout, one slice per row
+	boards  int                     // number of boards, keys are split in one colored cell per board when more than one
+}
+
+// keyboardLayouts holds the built-in keyboard layouts, each row lists its letter keys from left to right
+var keyboardLayouts = map[string][]string{
+	"qwerty":  {"qwertyuiop", "asdfghjkl", "zxcvbnm"},
+	"azerty":  {"azertyuiop", "qsdfghjklm", "wxcvbn"},
+	"qwertz":  {"qwertzuiop", "asdfghjkl", "yxcvbnm"},
+	"dvorak":  {"pyfgcrl", "aoeuidhtns", "qjkxbmwvz"},
+	"colemak": {"qwfpgjluy", "arstdhneio", "zxcvbkm"},
+	"greek":   {"ερτυθιοπ", "ασδφγηξκλ", "ζχψωβνμ"},
+}
+
+// languageLayouts holds the keyboard layout used for each language when none is selected
+var languageLayouts = map[string]string{
+	engine.Latin.Language: "qwerty",
+	engine.Greek.Language: "greek",
+}
+
+// layoutNames returns the names of the built-in keyboard layouts in alphabetical order
+func layoutNames() []string {
+	return slices.Sorted(maps.Keys(keyboardLayouts))
+}
+
+// newKeyboard creates a keyboard with the given rows of letter keys
+func newKeyboard(rows []string, boards int) keyboard {
+	k := keyboard{
+		letters: map[rune]keyboardLetter{},
+		layout:  make([][]keyboardLetter, len(rows)),
+		boards:  boards,
 	}
-	return keyboard{
-		letters: letters,
-		layout:  layout,
+	for i, row := range rows {
+		for j, r := range []rune(row) {
+			kl := keyboardLetter{
+				position: position{row: i, column: j},
+				letter:   letter{r: r, state: engine.NotChecked},
+				states:   make([]engine.LetterState, boards),
+			}
+			for b := range kl.states {
+				kl.states[b] = engine.NotChecked
+			}
+			k.letters[r] = kl
+			k.layout[i] = append(k.layout[i], kl)
+		}
 	}
+	return k
 }
 
-func (k *keyboard) updateLetterState(r rune, state int) {
+// updateLetterState sets the state of a letter on a board
+func (k *keyboard) updateLetterState(board int, r rune, state engine.LetterState) {
 	if kl, exists := k.letters[r]; exists {
 		row := kl.position.row
 		col := kl.position.column
-		style, exists := stateStyles[state]
-		if exists {
-			k.layout[row][col].letter.style = style // update the style based on the state
-			k.layout[row][col].letter.state = state // update the state of the letter
-		}
+		k.layout[row][col].states[board] = state
+		k.layout[row][col].letter.state = state
 	}
 }
 
-func (k *keyboard) getLetterState(r rune) int {
+func (k *keyboard) getLetterState(r rune) engine.LetterState {
 	if kl, exists := k.letters[r]; exists {
 		row := kl.position.row
 		col := kl.position.column
 		return k.layout[row][col].letter.state
 	}
-	return -1
+	return engine.NotChecked
+}
+
+// clone returns a copy of the keyboard that does not share the letter states
+func (k keyboard) clone() keyboard {
+	c := k
+	c.layout = make([][]keyboardLetter, len(k.layout))
+	for i, row := range k.layout {
+		c.layout[i] = slices.Clone(row)
+		for j := range row {
+			c.layout[i][j].states = slices.Clone(row[j].states)
+		}
+	}
+	return c
 }
 
 func (k *keyboard) reset() {
 	for r, row := range k.layout {
 		for c := range row {
-			k.layout[r][c].letter.style = stateStyles[notChecked] // reset style to not checked
-			k.layout[r][c].letter.state = notChecked              // reset state to not checked
+			k.layout[r][c].letter.state = engine.NotChecked // reset state to not checked
+			for i := range k.layout[r][c].states {
+				k.layout[r][c].states[i] = engine.NotChecked
+			}
 		}
 	}
 }
 
-func (k *keyboard) render() string {
-	rows := make([]string, len(k.layout))
+// action keys shown around the last row of letters, named after the key press they stand for
+const (
+	keyEnter     = "enter"
+	keyBackspace = "backspace"
+)
+
+// keyCell is a rendered key of the keyboard
+type keyCell struct {
+	key     string // letter or action key, as the key press it stands for
+	content string
+}
+
+// cells renders the keys of each row, with the enter and backspace keys around the last row of letters
+func (k *keyboard) cells() [][]keyCell {
+	rows := make([][]keyCell, len(k.layout))
 	for i, row := range k.layout {
-		letters := make([]string, len(row))
-		for j, kl := range row {
-			letters[j] = kl.letter.style.Render(string(kl.letter.r))
+		for _, kl := range row {
+			content := stateStyles[kl.letter.state].Render(string(kl.letter.r))
+			if k.boards > 1 {
+				content = renderSplitKey(kl.letter.r, kl.states)
+			}
+			rows[i] = append(rows[i], keyCell{key: string(kl.letter.r), content: content})
+		}
+	}
+	if n := len(rows); n > 0 {
+		rows[n-1] = slices.Concat(
+			[]keyCell{{key: keyEnter, content: k.renderAction("enter")}},
+			rows[n-1],
+			[]keyCell{{key: keyBackspace, content: k.renderAction("⌫")}},
+		)
+	}
+	return rows
+}
+
+// renderAction renders an action key with the same height as the letter keys
+func (k *keyboard) renderAction(label string) string {
+	if k.boards > 1 {
+		lines := (k.boards + splitKeyWidth - 1) / splitKeyWidth
+		return splitKeyStyles[engine.NotChecked].Padding(0, 1).Height(lines).Render(label)
+	}
+	return defaultStyle.Render(label)
+}
+
+// gap returns the number of columns between two keys of a row
+func (k *keyboard) gap() int {
+	if k.boards > 1 {
+		return 1 // split keys have no border, keep them apart
+	}
+	return 0
+}
+
+func (k *keyboard) render() string {
+	cells := k.cells()
+	rows := make([]string, len(cells))
+	for i, row := range cells {
+		keys := make([]string, 0, 2*len(row))
+		for j, c := range row {
+			if j > 0 && k.gap() > 0 {
+				keys = append(keys, strings.Repeat(" ", k.gap()))
+			}
+			keys = append(keys, c.content)
 		}
-		rows[i] = lipgloss.JoinHorizontal(lipgloss.Left, letters...)
+		rows[i] = lipgloss.JoinHorizontal(lipgloss.Top, keys...)
 	}
 	return lipgloss.JoinVertical(lipgloss.Center, rows...)
 }
+
+// layers returns one layer per key, placed where render draws it, so a click can be matched to its key.
+// The layers are identified by keyLayerPrefix followed by the key.
+func (k *keyboard) layers() []*lipgloss.Layer {
+	cells := k.cells()
+	widths := make([]int, len(cells))
+	for i, row := range cells {
+		for j, c := range row {
+			if j > 0 {
+				widths[i] += k.gap()
+			}
+			widths[i] += lipgloss.Width(c.content)
+		}
+	}
+	width := slices.Max(widths)
+	var layers []*lipgloss.Layer
+	y := 0
+	for i, row := range cells {
+		x := centerOffset(width, widths[i]) // rows are centered by render
+		height := 0
+		for _, c := range row {
+			layers = append(layers, lipgloss.NewLayer(c.content).ID(keyLayerPrefix+c.key).X(x).Y(y))
+			x += lipgloss.Width(c.content) + k.gap()
+			height = max(height, lipgloss.Height(c.content))
+		}
+		y += height
+	}
+	return layers
+}
+
+// splitKeyWidth is the number of cells of a split key line, each board gets one or more cells
+const splitKeyWidth = 4
+
+// renderSplitKey renders a key of a multi-board game as lines of cells colored by the state of the letter
+// on each board, in board order: two cells per board for two boards, one line of four cells for four
+// boards and two lines for eight
+func renderSplitKey(r rune, states []engine.LetterState) string {
+	perLine := min(len(states), splitKeyWidth)
+	cellsPerBoard := splitKeyWidth / perLine
+	var lines []string
+	for first := 0; first < len(states); first += perLine {
+		var sb strings.Builder
+		for c := range splitKeyWidth {
+			text := " "
+			if first == 0 && c == 1 {
+				text = string(r) // the letter goes on the first line, close to the middle
+			}
+			sb.WriteString(splitKeyStyles[states[first+c/cellsPerBoard]].Render(text))
+		}
+		lines = append(lines, sb.String())
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, lines...)
+}
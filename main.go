@@ -2,35 +2,22 @@
 package main
 
 import (
+	"errors"
+	"flag"
 	"fmt"
 	"os"
-
-	tea "charm.land/bubbletea/v2"
-	"github.com/charmbracelet/log"
+	_ "time/tzdata" // embed the timezone database so -tz works the same on every machine
 )
 
 func main() {
-	if err := os.Remove("debug.log"); err != nil && !os.IsNotExist(err) {
-		fmt.Println("fatal:", err)
-		os.Exit(1)
-	}
-	// create a logger that writes to a file
-	f, err := tea.LogToFile("debug.log", "debug")
-	if err != nil {
-		fmt.Println("fatal:", err)
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		fmt.Fprintln(os.Stderr, "fatal:", err)
+		if errors.Is(err, ErrUsage) || errors.Is(err, ErrInvalidConfig) {
+			os.Exit(2)
+		}
 		os.Exit(1)
 	}
-	//nolint:errcheck
-	defer f.Close()
-	logger := log.NewWithOptions(f, log.Options{
-		ReportTimestamp: true,
-		Level:           log.DebugLevel,
-	})
-	// create a new bubbletea program with our model
-	p := tea.NewProgram(newModel(logger))
-	logger.Info("==== Starting lexis ====")
-	// run the program
-	if _, err := p.Run(); err != nil {
-		panic(err)
-	}
 }
@@ -2,8 +2,13 @@
 package main
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"charm.land/bubbles/v2/help"
 	"charm.land/bubbles/v2/key"
@@ -12,22 +17,51 @@ import (
 	"charm.land/lipgloss/v2"
 	"github.com/charmbracelet/log"
 	"github.com/davecgh/go-spew/spew"
+	"github.com/ieroNo47/lexis/engine"
 )
 
 const (
 	stateLoading = iota
 	stateRowNotFull
 	stateInvalidWord
+	stateHardMode
+	stateResume
+	stateHint
 	statePlaying
+	stateLoadError  // the answer provider could not pick the answers, the error screen offers to retry
+	stateCheckError // the answer provider could not check the guess
+)
+
+// how long the answer provider is given to pick the answers and to check a guess
+const (
+	loadTimeout  = 10 * time.Second
+	checkTimeout = 5 * time.Second
 )
 
 type model struct {
-	game    game
-	log     *log.Logger
-	help    help.Model
-	keys    keyMap
-	state   int
-	spinner spinner.Model
+	game       game
+	log        *log.Logger
+	help       help.Model
+	keys       keyMap
+	state      int
+	spinner    spinner.Model
+	message    string // message shown in the error popup when a guess breaks the hard mode rules or cannot be checked
+	loadErr    error  // reason the answers could not be picked, shown on the error screen
+	retries    int    // number of times picking the answers has been retried
+	hint       string // last hint, shown in the result bar until the next guess
+	notice     string // short notice, shown in the result bar until the next key press
+	themes     map[string]theme
+	theme      string    // name of the current theme
+	colorblind bool      // mark the letter states by shape and use an orange and blue palette
+	animate    bool      // play the tile flip and row shake animations
+	anim       animation // animation being played
+	stats      stats
+	showStats  bool       // whether the stats screen is open
+	saved      *savedGame // game saved in a previous session, offered for resuming
+	rotation   *rotation  // answers played by the profile, nil if the answers may repeat
+	savePath   string
+	shared     bool // whether the result has been copied to the clipboard
+	debug      bool // whether the internal state, including the answer, is shown in the result bar
 }
 
 // initCompleteMsg is a message that is sent when the game initialization is complete and the answer is ready
@@ -39,22 +73,44 @@ type rowNotFullMsg bool
 // invalidWordMsg is a message that is sent when the user tries to submit a guess but the word is not valid
 type invalidWordMsg bool
 
+// hardModeMsg is a message that is sent when the user submits a guess that does not use the revealed hints in hard mode
+type hardModeMsg string
+
 // validWordMsg is a message that is sent when the user submits a valid guess
 type validWordMsg bool
 
+// loadFailedMsg is a message that is sent when the answer provider could not pick the answers, with the reason
+type loadFailedMsg struct{ err error }
+
+// checkFailedMsg is a message that is sent when the answer provider could not check a guess, with the reason
+type checkFailedMsg struct{ err error }
+
+// statsSavedMsg is a message that is sent when the stats file has been written, with the error if it failed
+type statsSavedMsg struct{ err error }
+
+// gameSavedMsg is a message that is sent when the save file has been written or removed, with the error if it failed
+type gameSavedMsg struct{ err error }
+
 // Init initializes the model and starts the game by getting the answer from the answer provider
 func (m model) Init() tea.Cmd {
 	cmds := []tea.Cmd{
 		m.spinner.Tick,
 	}
+	cmds = append(cmds, m.loadCmd())
+	return tea.Batch(cmds...)
+}
 
-	answerCmd := func() tea.Msg {
+// loadCmd returns a command that picks the answers, giving up after loadTimeout
+func (m model) loadCmd() tea.Cmd {
+	return func() tea.Msg {
 		m.log.Debug("[Init]")
-		m.game.initProvider()
+		ctx, cancel := context.WithTimeout(context.Background(), loadTimeout)
+		defer cancel()
+		if err := m.game.Init(ctx); err != nil {
+			return loadFailedMsg{err: err}
+		}
 		return initCompleteMsg(true)
 	}
-	cmds = append(cmds, answerCmd)
-	return tea.Batch(cmds...)
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -64,20 +120,65 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// === INIT ===
 	case initCompleteMsg:
 		m.log.Debug("Initialization complete")
-		m.game.start()
+		m.game.Start()
 		m.state = statePlaying
+		if err := m.game.Fallback(); err != nil {
+			m.log.Warn("Word service unavailable, using the local word list", "err", err)
+			m.notice = "word service offline, using the local word list"
+		}
+		if m.saved != nil && m.game.canRestore(*m.saved) {
+			m.state = stateResume
+		}
+		return m, nil
+	case loadFailedMsg:
+		m.log.Error("Failed to pick the answers", "err", msg.err, "retries", m.retries)
+		m.state = stateLoadError
+		m.loadErr = msg.err
 		return m, nil
+	// === MOUSE ===
+	case tea.MouseClickMsg:
+		return m.click(msg)
 	// === WINDOW RESIZE ===
 	case tea.WindowSizeMsg:
 		oHorizontal := updateStyles(msg)
 		m.help.SetWidth(msg.Width - oHorizontal)
 		m.log.Debug("Window resized", "width", msg.Width, "height", msg.Height)
 	case tea.KeyPressMsg:
+		m.notice = ""
+		// a key press ends the animation being played, so it applies to the game as shown
+		m.stopAnimation()
+		// any key other than quit closes the stats screen
+		if m.showStats && !key.Matches(msg, m.keys.Quit) {
+			m.showStats = false
+			return m, nil
+		}
+		// the error screen only accepts retry and quit
+		if m.state == stateLoadError && !key.Matches(msg, m.keys.Quit) {
+			return m.updateLoadError(msg)
+		}
+		// the resume prompt only accepts yes, no and quit
+		if m.state == stateResume && !key.Matches(msg, m.keys.Quit) {
+			return m.updateResume(msg)
+		}
+		// the hint menu only accepts the hint choices, any other key closes it
+		if m.state == stateHint && !key.Matches(msg, m.keys.Quit) {
+			return m.updateHint(msg)
+		}
 		switch {
 		// === QUIT ===
 		case key.Matches(msg, m.keys.Quit):
+			if m.state != stateResume && m.game.hasProgress() {
+				if err := m.saveGame()(); err != nil {
+					m.log.Error("Failed to save game", "err", err)
+				}
+			}
 			m.log.Info("==== Bye! ====")
 			return m, tea.Quit
+		// === SHARE ===
+		case m.game.IsFinished() && key.Matches(msg, m.keys.Share):
+			m.log.Info("Copying result to clipboard")
+			m.shared = true
+			return m, copyToClipboard(shareText(m.game, m.colorblind))
 		// === LETTERS ===
 		case key.Matches(msg, m.keys.Letter):
 			if m.state != stateLoading {
@@ -94,22 +195,65 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			} else {
 				m.log.Info("Cannot delete while loading")
 			}
+		// === STATS ===
+		case key.Matches(msg, m.keys.Stats):
+			m.showStats = true
+		// === HARD MODE ===
+		case key.Matches(msg, m.keys.HardMode):
+			if m.state != stateLoading && !m.game.ToggleHardMode() {
+				m.log.Info("Hard mode can only be toggled before the first guess")
+			}
+		// === HINT ===
+		case key.Matches(msg, m.keys.Hint):
+			if m.state == stateLoading || !m.game.InProgress() {
+				return m, nil
+			}
+			if m.game.HintsLeft() == 0 {
+				m.hint = "No hints left"
+				return m, nil
+			}
+			m.state = stateHint
+		// === THEME ===
+		case key.Matches(msg, m.keys.Theme):
+			m.nextTheme()
 		// === RESTART ===
 		case key.Matches(msg, m.keys.Restart):
+			if m.state == stateLoading {
+				m.log.Info("Cannot restart while loading")
+				return m, nil
+			}
+			// the finished game is already in the stats, replaying its answer would count it twice
+			if m.game.IsFinished() {
+				m.log.Info("Cannot restart a finished game")
+				return m, nil
+			}
 			m.log.Info("==== Restarting game ====")
 			m.game.reset()
+			m.shared = false
+			m.hint = ""
 		// === SUBMIT ===
 		case key.Matches(msg, m.keys.Submit):
+			// the engine is only read by the submit command, nothing else may change it until it returns
+			if m.state == stateLoading || !m.game.InProgress() {
+				return m, nil
+			}
 			cmds := []tea.Cmd{m.spinner.Tick}
 			m.state = stateLoading
 			submitCmd := func() tea.Msg {
-				err := m.game.rowReady()
+				ctx, cancel := context.WithTimeout(context.Background(), checkTimeout)
+				defer cancel()
+				err := m.game.rowReady(ctx)
 				if err != nil {
-					if errors.Is(err, ErrRowNotFull) {
+					var herr engine.HardModeError
+					if errors.Is(err, engine.ErrRowNotFull) {
 						return rowNotFullMsg(true)
-					} else if errors.Is(err, ErrInvalidWord) {
+					} else if errors.Is(err, engine.ErrInvalidWord) {
 						return invalidWordMsg(true)
+					} else if errors.As(err, &herr) {
+						return hardModeMsg(herr.Error())
 					}
+					// the provider could not tell whether the word is valid
+					return checkFailedMsg{err: err}
 				}
 				return validWordMsg(true)
 			}
@@ -123,16 +267,57 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.spinner, cmd = m.spinner.Update(msg)
 			return m, cmd
 		}
+	// === ANIMATION TICK ===
+	case animationTickMsg:
+		if msg.id != m.anim.id || !m.animating() {
+			return m, nil
+		}
+		m.anim.frame++
+		if m.anim.frame >= m.anim.frames(m.game.WordLength()) {
+			m.stopAnimation()
+			return m, nil
+		}
+		return m, m.anim.tick()
 	// === SUBMIT RESULTS ===
 	case rowNotFullMsg:
 		m.state = stateRowNotFull
-		return m, nil
+		return m, m.startAnimation(animationShake, nil)
 	case invalidWordMsg:
 		m.state = stateInvalidWord
+		return m, m.startAnimation(animationShake, nil)
+	case hardModeMsg:
+		m.state = stateHardMode
+		m.message = string(msg)
+		return m, nil
+	case checkFailedMsg:
+		m.state = stateCheckError
+		m.message = providerError(msg.err)
 		return m, nil
 	case validWordMsg:
 		m.state = statePlaying
-		m.game.Submit()
+		m.hint = ""
+		// the keys keep their states until the tiles of the guess have turned over
+		kb := m.game.keyboard.clone()
+		if err := m.game.Submit(); err != nil {
+			return m, nil
+		}
+		flip := m.startAnimation(animationFlip, &kb)
+		if m.game.IsFinished() {
+			return m, tea.Batch(m.recordGame(), m.recordAnswers(), m.saveGameCmd(), flip)
+		}
+		return m, tea.Batch(m.saveGameCmd(), flip)
+	case statsSavedMsg:
+		if msg.err != nil {
+			m.log.Error("Failed to save stats", "err", msg.err)
+		}
+	case gameSavedMsg:
+		if msg.err != nil {
+			m.log.Error("Failed to save game", "err", msg.err)
+		}
+	case historySavedMsg:
+		if msg.err != nil {
+			m.log.Error("Failed to save the answer history", "err", msg.err)
+		}
 	default:
 		// if log level is debug, print the current string in the active row
 		if m.log.GetLevel() == log.DebugLevel {
@@ -150,9 +335,16 @@ func (m model) View() tea.View {
 	v := tea.NewView("")
 	v.WindowTitle = "lexis"
 	v.AltScreen = true
+	v.MouseMode = tea.MouseModeCellMotion // clicks on the on-screen keyboard
+	v.SetContent(m.compositor().Render())
+	return v
+}
 
+// compositor lays out the screen as layers: the main view, the keys of the on-screen keyboard drawn over it
+// so clicks can be matched to them, and the popup on top
+func (m model) compositor() *lipgloss.Compositor {
 	// header
-	header := headerStyle.Render("lexis")
+	header := headerStyle.Render(m.title())
 	var resultS string
 	var resultRow string
 	rowIndex, colIndex, _ := m.game.debugState()
@@ -160,49 +352,96 @@ func (m model) View() tea.View {
 	var showPopup bool
 	var popupStyle lipgloss.Style
 
-	if m.game.isWon() {
-		popupText = fmt.Sprintf("You won in %d/%d attempts!", rowIndex+1, len(m.game.grid.words))
+	if m.state == stateLoadError {
+		popupText = fmt.Sprintf("Could not start the game\n\n%s\n\n%s",
+			lipgloss.Wrap(providerError(m.loadErr), popupTextWidth, ""), popupHint(popUpStyleError, "r: retry • ctrl+c/esc: quit"))
+		popupStyle = popUpStyleError
+		showPopup = true
+	} else if m.state == stateResume {
+		popupText = fmt.Sprintf("Resume your previous game?\n\n%s", popupHint(popUpStyleStats, "y: resume • n: new game"))
+		popupStyle = popUpStyleStats
+		showPopup = true
+	} else if m.state == stateHint {
+		popupText = fmt.Sprintf("Hints left: %d/%d\n\n1: reveal a letter\n2: count possible answers\n3: name an absent letter\n\n%s",
+			m.game.HintsLeft(), m.game.HintsLeft()+len(m.game.Hints()), popupHint(popUpStyleStats, "Press any other key to cancel"))
+		popupStyle = popUpStyleStats
+		showPopup = true
+	} else if m.showStats {
+		popupText = m.statsView()
+		popupStyle = popUpStyleStats
+		showPopup = true
+	} else if m.animating() {
+		// the outcome of a guess is shown once its animation has ended
+	} else if m.game.IsWon() {
+		popupText = fmt.Sprintf("You won in %d/%d attempts!", len(m.game.Words()), m.game.Rows())
 		popupStyle = popUpStyleWin
+		popupText = fmt.Sprintf("%s\n\n%s", popupText, popupHint(popupStyle, m.finishedHint()))
 		showPopup = true
-	} else if m.game.isLost() {
-		popupText = fmt.Sprintf("Better luck next time!\nThe answer was: %s", m.game.Answer())
+	} else if m.game.IsLost() {
+		popupText = fmt.Sprintf("Better luck next time!\nThe answer was: %s", m.game.Answers()[0])
+		if answers := m.game.Answers(); len(answers) > 1 {
+			popupText = fmt.Sprintf("Better luck next time!\nThe answers were: %s", strings.Join(answers, ", "))
+		}
 		popupStyle = popUpStyleLoss
+		popupText = fmt.Sprintf("%s\n\n%s", popupText, popupHint(popupStyle, m.finishedHint()))
 		showPopup = true
-	} else if m.state == stateRowNotFull || m.state == stateInvalidWord {
-		if m.state == stateRowNotFull {
+	} else if m.state == stateRowNotFull || m.state == stateInvalidWord || m.state == stateHardMode || m.state == stateCheckError {
+		hint := "Press any key to continue"
+		switch m.state {
+		case stateRowNotFull:
 			popupText = "Row is not full!"
-		} else {
+		case stateInvalidWord:
 			popupText = "Invalid word!"
+		case stateHardMode:
+			popupText = strings.ToUpper(m.message[:1]) + m.message[1:] + "!"
+		case stateCheckError:
+			popupText = "Could not check the word!\n\n" + lipgloss.Wrap(m.message, popupTextWidth, "")
+			hint = "Press enter to try again"
 		}
 		popupStyle = popUpStyleError
-		popupHelpStyle := lipgloss.NewStyle().Foreground(popupStyle.GetForeground()).Faint(true).Italic(true)
-		popupText = fmt.Sprintf("%s\n\n%s", popupText, popupHelpStyle.Render("Press any key to continue"))
+		popupText = fmt.Sprintf("%s\n\n%s", popupText, popupHint(popupStyle, hint))
 		showPopup = true
 	}
 
 	if m.state == stateLoading {
-		resultRow = resultBarStyleLoading.Render(m.spinner.View())
-	} else {
+		loading := m.spinner.View()
+		if m.retries > 0 {
+			loading += fmt.Sprintf(" retry %d", m.retries)
+		}
+		resultRow = resultBarStyleLoading.Render(loading)
+	} else if m.debug {
 		// debug row
 		resultS = fmt.Sprintf("Row: %d, Col: %d, RL: %d, L: %c, A: %s",
 			rowIndex,
 			colIndex,
-			len(m.game.grid.words[rowIndex])-1,
-			m.game.grid.words[rowIndex][colIndex].r,
-			m.game.Answer())
+			m.game.WordLength()-1,
+			m.game.grids[m.game.active()].words[rowIndex][colIndex].r,
+			strings.Join(m.game.Answers(), ","))
 		resultRow = resultBarStyleNormal.Render(resultS)
+	} else {
+		resultRow = m.statusBar()
 	}
 	helpRow := helpBarStyle.Render(m.help.View(m.keys))
+	gridView := m.game.render(m.anim)
+	kb := m.game.keyboard
+	if m.anim.keyboard != nil {
+		kb = *m.anim.keyboard
+	}
+	keyboardView := kb.render()
 	view := lipgloss.JoinVertical(lipgloss.Center,
 		header,
-		m.game.grid.render(),
-		m.game.keyboard.render(),
+		gridView,
+		keyboardView,
 		resultRow,
 		helpRow)
 
-	// main layer
+	// main layer, with the keys at the position of the keyboard in the view
+	keyboardX := containerOffset(lipgloss.Width(view)) + centerOffset(lipgloss.Width(view), lipgloss.Width(keyboardView))
+	keyboardY := containerStyle.GetMarginTop() + containerStyle.GetBorderTopSize() + containerStyle.GetPaddingTop() +
+		lipgloss.Height(header) + lipgloss.Height(gridView)
 	layers := []*lipgloss.Layer{
 		lipgloss.NewLayer(containerStyle.Render(view)).X(0).Y(0),
+		lipgloss.NewLayer("", kb.layers()...).X(keyboardX).Y(keyboardY),
 	}
 
 	// popup layer
@@ -213,31 +452,270 @@ func (m model) View() tea.View {
 
 		// X is set to half the container width minus half the popup width to center the popup horizontally
 		popupX := containerStyle.GetWidth()/2 - (popupWidth / 2)
-		// Y is set to half the view height minus half the popup height to center the popup vertically
-		// (arrived at this minus 5 offset through visual testing)
-		popupY := lipgloss.Height(view)/2 - (popupHeight / 2) - 5
+		// Y is set to the middle of the grid minus half the popup height to center the popup over the grid,
+		// whatever the number of rows
+		popupY := lipgloss.Height(header) + lipgloss.Height(gridView)/2 - (popupHeight / 2)
 
-		layers = append(layers, lipgloss.NewLayer(renderedPopup).X(popupX).Y(popupY).Z(1))
+		// the popup has an id so clicks on it do not reach the keys under it
+		layers = append(layers, lipgloss.NewLayer(renderedPopup).ID(popupLayer).X(popupX).Y(popupY).Z(1))
 	}
 
-	comp := lipgloss.NewCompositor(layers...)
+	return lipgloss.NewCompositor(layers...)
+}
 
-	v.SetContent(comp.Render())
-	// v.SetContent(containerStyle.Render(view))
-	return v
+// updateLoadError handles the keys of the error screen shown when the answers could not be picked
+func (m model) updateLoadError(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	if !key.Matches(msg, m.keys.Retry) {
+		return m, nil
+	}
+	m.retries++
+	m.log.Info("Retrying to pick the answers", "retry", m.retries)
+	m.state = stateLoading
+	return m, tea.Batch(m.spinner.Tick, m.loadCmd())
+}
+
+// providerError returns the reason the answer provider failed, as shown to the player
+func providerError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "The word list did not answer in time."
+	}
+	return err.Error()
+}
+
+// updateResume handles the answer to the resume prompt
+func (m model) updateResume(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case key.Matches(msg, m.keys.Confirm):
+		if err := m.game.restore(*m.saved); err != nil {
+			m.log.Error("Failed to restore saved game", "err", err)
+		}
+	case key.Matches(msg, m.keys.Decline):
+		m.log.Info("Discarding saved game")
+		if err := removeSavedGame(m.savePath); err != nil {
+			m.log.Error("Failed to remove saved game", "err", err)
+		}
+		m.saved = nil
+		m.state = statePlaying
+		return m, nil
+	default:
+		return m, nil
+	}
+	m.saved = nil
+	m.state = statePlaying
+	return m, nil
+}
+
+// recordAnswers adds the answers of a finished game to the history of the profile, so they are not picked again,
+// and returns a command that saves it. Answers are only recorded once the game is over, so the history never
+// holds the answer of a game in progress.
+func (m model) recordAnswers() tea.Cmd {
+	if m.rotation == nil {
+		return nil
+	}
+	return m.rotation.record(m.game.Answers())
+}
+
+// saveGame returns a function that saves the game, or removes the save file if there is nothing left to resume
+func (m model) saveGame() func() error {
+	s, ok := m.game.snapshot()
+	path := m.savePath
+	resumable := ok && m.game.hasProgress()
+	return func() error {
+		if !resumable {
+			return removeSavedGame(path)
+		}
+		return writeSavedGame(path, s)
+	}
 }
 
-// newModel creates a new model with the given logger and initializes the answer provider and spinner
-func newModel(logger *log.Logger) model {
-	provider := newRandomAnswerProvider()
+// saveGameCmd returns a command that saves the game in the background
+func (m model) saveGameCmd() tea.Cmd {
+	save := m.saveGame()
+	return func() tea.Msg {
+		return gameSavedMsg{err: save()}
+	}
+}
+
+// recordGame adds the finished game to the stats and returns a command that saves them
+func (m *model) recordGame() tea.Cmd {
+	m.stats.add(newGameRecord(m.game, time.Now()))
+	s := m.stats
+	return func() tea.Msg {
+		return statsSavedMsg{err: s.save()}
+	}
+}
+
+// statsView renders the stats screen with the totals and a bar chart of the guess distribution
+func (m model) statsView() string {
+	sum := m.stats.summarize(m.game.Rows(), len(m.game.Boards()))
+	totals := fmt.Sprintf("Played: %d  Win %%: %d  Streak: %d  Max Streak: %d  Hints: %d",
+		sum.played, sum.winPercent(), sum.currentStreak, sum.maxStreak, sum.hints)
+	maxCount := slices.Max(sum.distribution)
+	bars := make([]string, len(sum.distribution))
+	for i, count := range sum.distribution {
+		width := 1
+		if maxCount > 0 {
+			width = max(1, count*statsBarWidth/maxCount)
+		}
+		bar := statsBarStyle.Render(strings.Repeat(" ", width))
+		bars[i] = fmt.Sprintf("%2d %s %d", i+1, bar, count)
+	}
+	chart := lipgloss.JoinVertical(lipgloss.Left, bars...)
+	return fmt.Sprintf("Statistics\n\n%s\n\nGuess Distribution\n\n%s\n\n%s",
+		totals, chart, popupHint(popUpStyleStats, "Press any key to close"))
+}
+
+// statusBar renders the result bar with the attempt counter and the outcome of the last action
+func (m model) statusBar() string {
+	attempts := len(m.game.Words())
+	switch {
+	case m.state == stateResume:
+		return resultBarStyleNormal.Render("Saved game found")
+	case m.state == stateLoadError:
+		return resultBarStyleError.Render("Could not start the game")
+	case m.anim.kind == animationFlip:
+		// the guess being revealed, the outcome is shown once its tiles have turned over
+		return resultBarStyleNormal.Render(fmt.Sprintf("Attempt %d/%d", attempts, m.game.Rows()))
+	case m.game.IsWon():
+		status := fmt.Sprintf("Solved in %d/%d", attempts, m.game.Rows())
+		if m.shared {
+			status += " · copied to clipboard"
+		}
+		return resultBarStyleWin.Render(status)
+	case m.game.IsLost():
+		status := fmt.Sprintf("Out of attempts X/%d", m.game.Rows())
+		if m.shared {
+			status += " · copied to clipboard"
+		}
+		return resultBarStyleLoss.Render(status)
+	}
+	status := fmt.Sprintf("Attempt %d/%d", attempts+1, m.game.Rows())
+	if m.hint != "" {
+		status += " · " + m.hint
+	}
+	if m.notice != "" {
+		status += " · " + m.notice
+	}
+	switch m.state {
+	case stateRowNotFull:
+		return resultBarStyleError.Render(status + " · row is not full")
+	case stateInvalidWord:
+		return resultBarStyleError.Render(status + " · not in word list")
+	case stateHardMode:
+		return resultBarStyleError.Render(status + " · " + m.message)
+	case stateCheckError:
+		return resultBarStyleError.Render(status + " · could not check the word")
+	}
+	return resultBarStyleNormal.Render(status)
+}
+
+// nextTheme switches to the next theme in alphabetical order
+func (m *model) nextTheme() {
+	names := slices.Sorted(maps.Keys(m.themes))
+	i := slices.Index(names, m.theme)
+	m.theme = names[(i+1)%len(names)]
+	applyTheme(m.themes[m.theme], m.colorblind)
+	m.notice = "theme: " + m.theme
+	m.log.Info("Theme switched", "theme", m.theme)
+}
+
+// finishedHint returns the help line of the win and loss popups
+func (m model) finishedHint() string {
+	if m.shared {
+		return "Copied to clipboard! • ctrl+s: stats"
+	}
+	return "c: copy result • ctrl+s: stats"
+}
+
+// updateHint handles the choice in the hint menu
+func (m model) updateHint(msg tea.KeyPressMsg) (tea.Model, tea.Cmd) {
+	m.state = statePlaying
+	var kind engine.HintKind
+	switch {
+	case key.Matches(msg, m.keys.HintLetter):
+		kind = engine.HintLetter
+	case key.Matches(msg, m.keys.HintCount):
+		kind = engine.HintCount
+	case key.Matches(msg, m.keys.HintAbsent):
+		kind = engine.HintAbsent
+	default:
+		return m, nil
+	}
+	h, err := m.game.hint(kind)
+	if err != nil {
+		m.log.Info("Hint not given", "err", err)
+		m.hint = strings.ToUpper(err.Error()[:1]) + err.Error()[1:]
+		return m, nil
+	}
+	m.hint = "Hint: " + h.String()
+	if len(m.game.Boards()) > 1 {
+		m.hint = fmt.Sprintf("Hint for board %d: %s", h.Board+1, h)
+	}
+	return m, m.saveGameCmd()
+}
+
+// popupHint renders a faint help line in the colors of the given popup style
+func popupHint(popupStyle lipgloss.Style, text string) string {
+	return lipgloss.NewStyle().Foreground(popupStyle.GetForeground()).Faint(true).Italic(true).Render(text)
+}
+
+// title returns the header text with the puzzle number and mode when applicable
+func (m model) title() string {
+	title := "lexis"
+	if n, ok := m.game.PuzzleNumber(); ok && m.game.State() != engine.Loading {
+		title = fmt.Sprintf("lexis #%d", n)
+	}
+	if n := len(m.game.Boards()); n > 1 {
+		title += fmt.Sprintf(" · %d boards", n)
+	}
+	if d := m.game.Difficulty(); d != engine.DifficultyNormal {
+		title += " · " + d
+	}
+	if m.game.HardMode() {
+		title += " · hard mode"
+	}
+	if m.game.State() != engine.Loading && m.game.Fallback() != nil {
+		title += " · offline"
+	}
+	return title
+}
+
+// newModel creates a new model with the given logger, answer providers (one per board), word checker, settings
+// and answer rotation and initializes the spinner. A nil checker leaves the guesses to the providers.
+func newModel(logger *log.Logger, providers []engine.AnswerProvider, checker engine.WordChecker, cfg config, st stats, saved *savedGame, rot *rotation) (model, error) {
+	g, err := newGame(providers, engine.Options{
+		Rows:       cfg.Rows,
+		WordLength: cfg.WordLength,
+		HardMode:   cfg.HardMode,
+		Hints:      cfg.Hints,
+		Checker:    checker,
+		Debug:      cfg.Debug,
+		Logger:     logger,
+	}, cfg.keyboardRows())
+	if err != nil {
+		return model{}, err
+	}
+	themes := cfg.themes()
+	applyTheme(themes[cfg.Theme], cfg.Colorblind)
 	s := spinner.New()
 	s.Spinner = spinner.Points
+	k := keys
+	k.Letter = letterKeys(g.Alphabet())
 	return model{
-		game:    newGame(provider, logger),
-		log:     logger,
-		help:    newHelp(),
-		keys:    keys,
-		state:   stateLoading,
-		spinner: s,
-	}
+		game:       g,
+		log:        logger,
+		help:       newHelp(),
+		keys:       k,
+		state:      stateLoading,
+		spinner:    s,
+		stats:      st,
+		saved:      saved,
+		rotation:   rot,
+		savePath:   cfg.SaveFile,
+		debug:      cfg.Debug,
+		themes:     themes,
+		theme:      cfg.Theme,
+		colorblind: cfg.Colorblind,
+		animate:    !cfg.ReducedMotion,
+	}, nil
 }
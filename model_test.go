@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"io"
+	"path/filepath"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/charmbracelet/log"
+	"github.com/ieroNo47/lexis/engine"
+)
+
+// newTestModel starts a game whose answer is picked from answers, with its files in a temporary directory
+func newTestModel(t *testing.T, answers, allowed []string, saved *savedGame) model {
+	t.Helper()
+	p, err := engine.NewDictionaryProvider(answers, allowed, engine.Latin)
+	if err != nil {
+		t.Fatalf("NewDictionaryProvider: %v", err)
+	}
+	dir := t.TempDir()
+	cfg := defaultConfig()
+	cfg.SaveFile = filepath.Join(dir, "save.json")
+	m, err := newModel(log.New(io.Discard), []engine.AnswerProvider{p}, p, cfg, stats{path: filepath.Join(dir, "stats.json")}, saved, nil)
+	if err != nil {
+		t.Fatalf("newModel: %v", err)
+	}
+	if err := m.game.Init(context.Background()); err != nil {
+		t.Fatalf("Init: %v", err)
+	}
+	return update(m, initCompleteMsg(true))
+}
+
+// submit types word and submits it as a valid guess
+func submit(m model, word string) model {
+	for _, r := range word {
+		m = update(m, tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	return update(m, validWordMsg(true))
+}
+
+func TestRestart(t *testing.T) {
+	restart := tea.KeyPressMsg{Code: 'r', Mod: tea.ModCtrl}
+	tests := []struct {
+		name        string
+		guesses     []string
+		wantGuesses int // guesses left after the restart
+		wantGames   int // games in the stats after the restart
+	}{
+		{"new game", nil, 0, 0},
+		{"game in progress", []string{"slate"}, 0, 0},
+		{"won game is not restarted", []string{"slate", "crane"}, 2, 1},
+		{"lost game is not restarted", []string{"slate", "slate", "slate", "slate", "slate", "slate"}, 6, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := newTestModel(t, []string{"crane"}, []string{"slate"}, nil)
+			for _, w := range tt.guesses {
+				m = submit(m, w)
+			}
+			m = update(m, restart)
+			if got := len(m.game.Words()); got != tt.wantGuesses {
+				t.Errorf("%d guesses after the restart, want %d", got, tt.wantGuesses)
+			}
+			if got := len(m.stats.Games); got != tt.wantGames {
+				t.Errorf("%d games in the stats after the restart, want %d", got, tt.wantGames)
+			}
+			// replaying the answer after a restart must not record the game again
+			if !m.game.IsFinished() {
+				m = submit(m, "crane")
+			}
+			if got := len(m.stats.Games); got != 1 {
+				t.Errorf("%d games in the stats once the game is over, want 1", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+// mouse.go matches mouse clicks with the keys of the on-screen keyboard
+package main
+
+import (
+	"math"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+)
+
+// layer ids used for hit-testing
+const (
+	keyLayerPrefix = "key:"  // followed by the key, see keyboard.layers
+	popupLayer     = "popup" // covers the keys under the popup
+)
+
+// click handles a mouse click: a click on a key of the on-screen keyboard is handled as the key press it stands for,
+// so it goes through the same states and prompts as typing
+func (m model) click(msg tea.MouseClickMsg) (tea.Model, tea.Cmd) {
+	if msg.Button != tea.MouseLeft {
+		return m, nil
+	}
+	k, ok := strings.CutPrefix(m.compositor().Hit(msg.X, msg.Y).ID(), keyLayerPrefix)
+	if !ok {
+		return m, nil
+	}
+	m.log.Debug("Key clicked", "key", k)
+	switch k {
+	case keyEnter:
+		return m.Update(tea.KeyPressMsg{Code: tea.KeyEnter})
+	case keyBackspace:
+		return m.Update(tea.KeyPressMsg{Code: tea.KeyBackspace})
+	}
+	r := []rune(k)[0]
+	return m.Update(tea.KeyPressMsg{Code: r, Text: k})
+}
+
+// centerOffset returns the column where lipgloss.JoinVertical with lipgloss.Center places a block
+// of width inner in a block of width outer
+func centerOffset(outer, inner int) int {
+	return int(math.Round(float64(outer-inner) / 2))
+}
+
+// containerOffset returns the column where containerStyle places a view of the given width,
+// which depends on the terminal width set by updateStyles
+func containerOffset(width int) int {
+	left := containerStyle.GetMarginLeft() + containerStyle.GetBorderLeftSize() + containerStyle.GetPaddingLeft()
+	inner := containerStyle.GetWidth() - containerStyle.GetHorizontalBorderSize() - containerStyle.GetHorizontalPadding()
+	// lipgloss puts the odd column on the right when it centers a line
+	return left + max(0, inner-width)/2
+}
@@ -0,0 +1,68 @@
+// save.go saves an in-progress game to disk so it can be resumed in a later session
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/ieroNo47/lexis/engine"
+)
+
+// savedGame is an in-progress game as stored in the save file: the engine snapshot
+// and the letters typed in the current row. Letter states are computed again when the game is restored.
+type savedGame struct {
+	engine.Snapshot
+	Input string `json:"input"`
+}
+
+// defaultSavePath returns the path of the save file in the user's data directory
+func defaultSavePath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "save.json"), nil
+}
+
+// loadSavedGame reads the save file at path. It returns nil if there is no saved game.
+func loadSavedGame(path string) (*savedGame, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var s savedGame
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return &s, nil
+}
+
+// writeSavedGame writes the save file, replacing the previous one only once the new one is fully written
+func writeSavedGame(path string, s savedGame) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// removeSavedGame deletes the save file if there is one
+func removeSavedGame(path string) error {
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/ieroNo47/lexis/engine"
+)
+
+func TestSaveAndResume(t *testing.T) {
+	answers, allowed := []string{"crane", "slate", "pious"}, []string{"tipsy"}
+	m := newTestModel(t, answers, allowed, nil)
+	m = submit(m, "tipsy")
+	if _, err := m.game.hint(engine.HintAbsent); err != nil {
+		t.Fatalf("hint: %v", err)
+	}
+	for _, r := range "cr" {
+		m = update(m, tea.KeyPressMsg{Code: r, Text: string(r)})
+	}
+	s, ok := m.game.snapshot()
+	if !ok {
+		t.Fatal("snapshot() = false, want true")
+	}
+	if s.Input != "cr   " {
+		t.Errorf("snapshot().Input = %q, want %q", s.Input, "cr   ")
+	}
+
+	path := filepath.Join(t.TempDir(), "lexis", "save.json")
+	if err := writeSavedGame(path, s); err != nil {
+		t.Fatalf("writeSavedGame: %v", err)
+	}
+	saved, err := loadSavedGame(path)
+	if err != nil {
+		t.Fatalf("loadSavedGame: %v", err)
+	}
+	if saved == nil || !reflect.DeepEqual(*saved, s) {
+		t.Fatalf("loadSavedGame() = %+v, want %+v", saved, s)
+	}
+
+	r := newTestModel(t, answers, allowed, saved)
+	if r.state != stateResume {
+		t.Fatalf("state = %v with a saved game, want the resume prompt", r.state)
+	}
+	r = update(r, tea.KeyPressMsg{Code: 'y', Text: "y"})
+	if got, want := r.game.Answers(), m.game.Answers(); !slices.Equal(got, want) {
+		t.Errorf("Answers() = %v after resuming, want %v", got, want)
+	}
+	if got := r.game.Words(); !slices.Equal(got, []string{"tipsy"}) {
+		t.Errorf("Words() = %v after resuming, want [tipsy]", got)
+	}
+	if got, want := r.game.Hints(), m.game.Hints(); !slices.Equal(got, want) {
+		t.Errorf("Hints() = %v after resuming, want %v", got, want)
+	}
+	if got := r.game.rowString(); got != s.Input {
+		t.Errorf("typed row = %q after resuming, want %q", got, s.Input)
+	}
+
+	if err := removeSavedGame(path); err != nil {
+		t.Fatalf("removeSavedGame: %v", err)
+	}
+	if saved, err := loadSavedGame(path); saved != nil || err != nil {
+		t.Errorf("loadSavedGame() = %v, %v after removing it, want nil", saved, err)
+	}
+	if err := removeSavedGame(path); err != nil {
+		t.Errorf("removeSavedGame() = %v without a save file, want nil", err)
+	}
+}
+
+func TestCanRestore(t *testing.T) {
+	m := newTestModel(t, []string{"crane", "slate"}, []string{"tipsy"}, nil)
+	m = submit(m, "tipsy")
+	valid, ok := m.game.snapshot()
+	if !ok {
+		t.Fatal("snapshot() = false, want true")
+	}
+	tests := []struct {
+		name   string
+		change func(s *savedGame)
+		want   bool
+	}{
+		{"same game", func(s *savedGame) {}, true},
+		{"english saved before the language was stored", func(s *savedGame) { s.Language = "" }, true},
+		{"other language", func(s *savedGame) { s.Language = "el" }, false},
+		{"other mode", func(s *savedGame) { s.Mode = engine.ModeDaily }, false},
+		{"no answer", func(s *savedGame) { s.AnswerID = "" }, false},
+		{"guess of another length", func(s *savedGame) { s.Guesses = []string{"tipsy", "slates"} }, false},
+		{"typed row of another length", func(s *savedGame) { s.Input = "cr" }, false},
+		{"no rows left", func(s *savedGame) { s.Guesses = slices.Repeat([]string{"tipsy"}, 6) }, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := valid
+			s.Guesses = slices.Clone(valid.Guesses)
+			tt.change(&s)
+			if got := m.game.canRestore(s); got != tt.want {
+				t.Errorf("canRestore(%+v) = %v, want %v", s, got, tt.want)
+			}
+		})
+	}
+}
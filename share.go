@@ -0,0 +1,97 @@
+// share.go builds the spoiler-free result text and copies it to the clipboard
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	tea "charm.land/bubbletea/v2"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/ieroNo47/lexis/engine"
+)
+
+// shareSquares maps a letter state to the square used in the share text
+var shareSquares = map[engine.LetterState]string{
+	engine.Matched:    "🟩",
+	engine.Exists:     "🟨",
+	engine.NotMatched: "⬛",
+}
+
+// colorblindShareSquares are the squares of the share text in colorblind mode, matching the orange and blue palette
+var colorblindShareSquares = map[engine.LetterState]string{
+	engine.Matched:    "🟧",
+	engine.Exists:     "🟦",
+	engine.NotMatched: "⬛",
+}
+
+// shareText builds the result of a finished game without revealing any letters, e.g.
+//
+//	lexis #12 4/6* 💡2
+//
+// followed by one row of squares per guess. The score is X when the game is lost, * marks hard mode
+// and 💡 is followed by the number of hints used.
+// A multi-board game shows the number of boards after the puzzle number and the score of each board
+// instead of the squares, laid out like the boards on screen.
+// In colorblind mode the squares are orange and blue and a failed board is black instead of red.
+func shareText(g game, colorblind bool) string {
+	squares, failed := shareSquares, "🟥"
+	if colorblind {
+		squares, failed = colorblindShareSquares, shareSquares[engine.NotMatched]
+	}
+	var sb strings.Builder
+	sb.WriteString("lexis")
+	if n, ok := g.PuzzleNumber(); ok {
+		fmt.Fprintf(&sb, " #%d", n)
+	}
+	boards := g.Boards()
+	if len(boards) > 1 {
+		fmt.Fprintf(&sb, " ×%d", len(boards))
+	}
+	score := "X"
+	if g.IsWon() {
+		score = fmt.Sprint(len(g.Words()))
+	}
+	fmt.Fprintf(&sb, " %s/%d", score, g.Rows())
+	if g.HardMode() {
+		sb.WriteString("*")
+	}
+	if n := len(g.Hints()); n > 0 {
+		fmt.Fprintf(&sb, " 💡%d", n)
+	}
+	sb.WriteString("\n")
+	if len(boards) > 1 {
+		for i, b := range boards {
+			if i%boardsPerLine == 0 {
+				sb.WriteString("\n")
+			} else {
+				sb.WriteString(" ")
+			}
+			if b.IsWon() {
+				fmt.Fprintf(&sb, "%s%d", squares[engine.Matched], len(b.Guesses()))
+			} else {
+				sb.WriteString(failed + "X")
+			}
+		}
+		return sb.String()
+	}
+	for _, fb := range boards[0].Guesses() {
+		sb.WriteString("\n")
+		for _, l := range fb {
+			sb.WriteString(squares[l.State])
+		}
+	}
+	return sb.String()
+}
+
+// copyToClipboard returns a command that copies text to the system clipboard using OSC52 escape sequences,
+// which also works over SSH. The sequence is wrapped for tmux and screen when running inside them.
+func copyToClipboard(text string) tea.Cmd {
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+	return tea.Raw(seq.String())
+}
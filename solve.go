@@ -0,0 +1,199 @@
+// solve.go lets the solver play from the command line, to check word lists and compare openers
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/ieroNo47/lexis/engine"
+)
+
+// runSolve plays the given answer, the daily puzzle or every answer of the word list with the solver.
+// A single game is printed guess by guess, solving every answer prints a summary.
+func runSolve(args []string, stdout, stderr io.Writer) error {
+	var answer, opener string
+	var daily, all bool
+	var puzzle int
+	cfg, set, err := parseConfig("solve", args, stderr, func(fs *flag.FlagSet, flags *config) {
+		defaults := defaultConfig()
+		fs.StringVar(&answer, "answer", "", "solve the puzzle with this answer")
+		fs.BoolVar(&daily, "daily", false, "solve today's daily puzzle")
+		fs.IntVar(&puzzle, "puzzle", 0, "solve the daily puzzle with this number instead of today's")
+		fs.BoolVar(&all, "all", false, "solve every answer of the word list and print a summary")
+		fs.StringVar(&opener, "opener", "", "first guess of every game (default: the best one found by the solver)")
+		fs.StringVar(&flags.Answers, "answers", defaults.Answers, "path to the answer word list (default: built-in list)")
+		fs.StringVar(&flags.Allowed, "allowed", defaults.Allowed, "path to the allowed-guess word list (default: built-in list)")
+		fs.StringVar(&flags.Language, "lang", defaults.Language, fmt.Sprintf("language of the words, one of %v", engine.Languages()))
+		fs.StringVar(&flags.Timezone, "tz", defaults.Timezone, "IANA timezone used for the daily puzzle rollover, e.g. Europe/Athens or UTC")
+		fs.IntVar(&flags.WordLength, "length", defaults.WordLength, fmt.Sprintf("number of letters per word (%d-%d)", minWordLength, maxWordLength))
+		fs.IntVar(&flags.Rows, "rows", defaults.Rows, "number of attempts")
+		fs.Func("mode", "game mode, normal or hard (revealed hints must be used in later guesses)", modeFlag(flags))
+	})
+	if err != nil {
+		return err
+	}
+	daily = daily || set["puzzle"]
+	modes := 0
+	for _, on := range []bool{answer != "", daily, all} {
+		if on {
+			modes++
+		}
+	}
+	if modes != 1 {
+		return fmt.Errorf("%w: solve needs exactly one of -answer, -daily or -all", ErrUsage)
+	}
+	if set["tz"] && !daily {
+		return fmt.Errorf("%w: -tz only applies to the daily puzzle", ErrUsage)
+	}
+	// the solver does not depend on the provider, which is chosen by the flags above
+	cfg.Provider, cfg.Seed = providerRandom, 0
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	alphabet := cfg.alphabet()
+	dict, err := engine.LoadDictionaryProvider(cfg.Answers, cfg.Allowed, cfg.WordLength, alphabet)
+	if err != nil {
+		return err
+	}
+	// the solver compares words without accents or final forms
+	answers := make([]string, len(dict.Answers()))
+	for i, w := range dict.Answers() {
+		answers[i] = alphabet.Fold(w)
+	}
+	opener, answer = alphabet.Fold(opener), alphabet.Fold(answer)
+	ctx := context.Background()
+	if opener != "" {
+		if valid, err := dict.Valid(ctx, opener); err != nil {
+			return err
+		} else if !valid {
+			return fmt.Errorf("%w: opener %q is not in the word list", ErrUsage, opener)
+		}
+	}
+	if answer != "" && !slices.Contains(answers, answer) {
+		return fmt.Errorf("%w: %q is not in the answer list", ErrUsage, answer)
+	}
+
+	solver := engine.NewSolver(answers, dict.Allowed(), cfg.HardMode)
+	if opener != "" {
+		solver.SetOpener(opener)
+	}
+	opts := engine.Options{Rows: cfg.Rows, HardMode: cfg.HardMode}
+	if all {
+		return solveAll(ctx, stdout, solver, answers, opts)
+	}
+	var provider engine.AnswerProvider = &engine.StaticProvider{Word: answer}
+	if daily {
+		location, err := time.LoadLocation(cfg.Timezone)
+		if err != nil {
+			return fmt.Errorf("invalid timezone: %w", err)
+		}
+		provider = engine.NewDailyProvider(dict, location)
+	}
+	g, err := engine.NewGame(provider, opts)
+	if err != nil {
+		return err
+	}
+	if err := g.Init(ctx); err != nil {
+		return err
+	}
+	if dp, ok := provider.(*engine.DailyProvider); ok && puzzle != 0 {
+		// Init picks today's puzzle, replace it before the game starts
+		if err := dp.SetPuzzle(puzzle); err != nil {
+			return err
+		}
+	}
+	g.Start()
+	return solveOne(ctx, stdout, solver, g)
+}
+
+// solveOne plays a started game and prints each guess with its feedback and the number of answers left
+func solveOne(ctx context.Context, w io.Writer, solver *engine.Solver, g *engine.Game) error {
+	var sb strings.Builder
+	sb.WriteString("lexis")
+	if n, ok := g.PuzzleNumber(); ok {
+		fmt.Fprintf(&sb, " #%d", n)
+	}
+	sb.WriteString("\n\n")
+	solver.Reset()
+	for g.InProgress() {
+		guess := solver.Next()
+		if guess == "" {
+			return engine.ErrNoCandidates
+		}
+		fb, err := g.Guess(ctx, guess)
+		if err != nil {
+			return fmt.Errorf("guessing %q: %w", guess, err)
+		}
+		solver.Update(guess, fb.States())
+		fmt.Fprintf(&sb, "%d %s ", len(g.Guesses()), fb.Word())
+		for _, l := range fb {
+			sb.WriteString(shareSquares[l.State])
+		}
+		if !fb.Solved() {
+			fmt.Fprintf(&sb, " %d left", len(solver.Candidates()))
+		}
+		sb.WriteString("\n")
+	}
+	if g.IsWon() {
+		fmt.Fprintf(&sb, "\nSolved in %d/%d\n", len(g.Guesses()), g.Rows())
+	} else {
+		fmt.Fprintf(&sb, "\nFailed, the answer was %s\n", g.Answer())
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// solveAll plays one game for every answer and prints the average number of guesses and the failures
+func solveAll(ctx context.Context, w io.Writer, solver *engine.Solver, answers []string, opts engine.Options) error {
+	distribution := make([]int, opts.Rows)
+	var failures []string
+	won, total := 0, 0
+	for _, answer := range answers {
+		g, err := engine.NewGame(&engine.StaticProvider{Word: answer}, opts)
+		if err != nil {
+			return err
+		}
+		if err := g.Init(ctx); err != nil {
+			return err
+		}
+		g.Start()
+		guesses, err := solver.Play(ctx, g)
+		if err != nil {
+			return fmt.Errorf("solving %q: %w", answer, err)
+		}
+		if !g.IsWon() {
+			words := make([]string, len(guesses))
+			for i, fb := range guesses {
+				words[i] = fb.Word()
+			}
+			failures = append(failures, fmt.Sprintf("%s: %s", answer, strings.Join(words, " ")))
+			continue
+		}
+		won++
+		total += len(guesses)
+		distribution[len(guesses)-1]++
+	}
+
+	var sb strings.Builder
+	solver.Reset()
+	fmt.Fprintf(&sb, "Opener:  %s\n", solver.Next())
+	fmt.Fprintf(&sb, "Solved:  %d/%d\n", won, len(answers))
+	if won > 0 {
+		fmt.Fprintf(&sb, "Average: %.3f guesses\n", float64(total)/float64(won))
+	}
+	sb.WriteString("\nGuess distribution\n")
+	writeDistribution(&sb, distribution)
+	if len(failures) > 0 {
+		fmt.Fprintf(&sb, "\nFailed (%d)\n", len(failures))
+		for _, f := range failures {
+			fmt.Fprintf(&sb, "  %s\n", f)
+		}
+	}
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
@@ -0,0 +1,165 @@
+// stats.go records finished games and computes the player statistics
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// gameRecord is a finished game as stored in the stats file
+type gameRecord struct {
+	Date       time.Time `json:"date"`
+	Answer     string    `json:"answer"` // answers of every board separated by commas in a multi-board game
+	Guesses    []string  `json:"guesses"`
+	Won        bool      `json:"won"`
+	Mode       string    `json:"mode"`
+	Puzzle     int       `json:"puzzle,omitempty"` // puzzle number in daily mode
+	HardMode   bool      `json:"hardMode"`
+	Difficulty string    `json:"difficulty,omitempty"` // how common the answer is: easy, normal or hard
+	Hints      int       `json:"hints,omitempty"`      // number of hints used
+	Rows       int       `json:"rows"`
+	Boards     int       `json:"boards,omitempty"` // number of boards, 0 for a single board
+}
+
+// stats holds every finished game, in the order they were played
+type stats struct {
+	Games []gameRecord `json:"games"`
+	path  string       // file the stats are loaded from and saved to
+}
+
+// summary holds the statistics shown on the stats screen
+type summary struct {
+	played        int
+	won           int
+	currentStreak int
+	maxStreak     int
+	hints         int   // total number of hints used
+	distribution  []int // number of wins per number of guesses, index 0 is a win on the first guess
+}
+
+// dataDir returns the lexis directory in the user's data directory:
+// $XDG_DATA_HOME or ~/.local/share on Unix, %LocalAppData% on Windows
+func dataDir() (string, error) {
+	if dir := os.Getenv("XDG_DATA_HOME"); dir != "" {
+		return filepath.Join(dir, "lexis"), nil
+	}
+	if runtime.GOOS == "windows" {
+		if dir := os.Getenv("LocalAppData"); dir != "" {
+			return filepath.Join(dir, "lexis"), nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".local", "share", "lexis"), nil
+}
+
+// defaultStatsPath returns the path of the stats file in the user's data directory
+func defaultStatsPath() (string, error) {
+	dir, err := dataDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "stats.json"), nil
+}
+
+// loadStats reads the stats file at path. A missing file is not an error and returns empty stats.
+func loadStats(path string) (stats, error) {
+	s := stats{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return s, err
+	}
+	if err := json.Unmarshal(data, &s); err != nil {
+		return s, fmt.Errorf("%s: %w", path, err)
+	}
+	return s, nil
+}
+
+// save writes the stats file, replacing the previous one only once the new one is fully written
+func (s stats) save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.path)
+}
+
+// add appends a finished game to the stats
+func (s *stats) add(r gameRecord) {
+	s.Games = append(s.Games, r)
+}
+
+// summarize computes the statistics of the games played with the given number of boards,
+// with a guess distribution of at least rows entries
+func (s stats) summarize(rows, boards int) summary {
+	sum := summary{distribution: make([]int, rows)}
+	for _, r := range s.Games {
+		if max(r.Boards, 1) != boards {
+			continue
+		}
+		sum.played++
+		sum.hints += r.Hints
+		if !r.Won {
+			sum.currentStreak = 0
+			continue
+		}
+		sum.won++
+		sum.currentStreak++
+		sum.maxStreak = max(sum.maxStreak, sum.currentStreak)
+		if n := len(r.Guesses); n > 0 {
+			for len(sum.distribution) < n {
+				sum.distribution = append(sum.distribution, 0)
+			}
+			sum.distribution[n-1]++
+		}
+	}
+	return sum
+}
+
+// winPercent returns the percentage of games won, rounded down
+func (s summary) winPercent() int {
+	if s.played == 0 {
+		return 0
+	}
+	return s.won * 100 / s.played
+}
+
+// newGameRecord creates the record of a finished game
+func newGameRecord(g game, date time.Time) gameRecord {
+	r := gameRecord{
+		Date:       date,
+		Answer:     strings.Join(g.Answers(), ","),
+		Guesses:    g.Words(),
+		Won:        g.IsWon(),
+		Mode:       g.Mode(),
+		HardMode:   g.HardMode(),
+		Difficulty: g.Difficulty(),
+		Hints:      len(g.Hints()),
+		Rows:       g.Rows(),
+	}
+	if n, ok := g.PuzzleNumber(); ok {
+		r.Puzzle = n
+	}
+	if n := len(g.Boards()); n > 1 {
+		r.Boards = n
+	}
+	return r
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// record returns a finished game with n guesses, 0 for a lost game, on the given number of boards
+func record(n, boards int) gameRecord {
+	guesses := n
+	if n == 0 {
+		guesses = 6 // a lost game used every row
+	}
+	r := gameRecord{Won: n > 0, Guesses: make([]string, guesses), Rows: 6, Hints: 1}
+	if boards > 1 {
+		r.Boards = boards
+	}
+	return r
+}
+
+func TestSummarize(t *testing.T) {
+	tests := []struct {
+		name   string
+		games  []gameRecord
+		rows   int
+		boards int
+		want   summary
+	}{
+		{"no games", nil, 6, 1, summary{distribution: make([]int, 6)}},
+		{"wins", []gameRecord{record(3, 1), record(4, 1), record(3, 1)}, 6, 1,
+			summary{played: 3, won: 3, currentStreak: 3, maxStreak: 3, hints: 3, distribution: []int{0, 0, 2, 1, 0, 0}}},
+		{"loss ends the streak", []gameRecord{record(2, 1), record(5, 1), record(0, 1), record(1, 1)}, 6, 1,
+			summary{played: 4, won: 3, currentStreak: 1, maxStreak: 2, hints: 4, distribution: []int{1, 1, 0, 0, 1, 0}}},
+		{"loss last", []gameRecord{record(2, 1), record(0, 1)}, 6, 1,
+			summary{played: 2, won: 1, currentStreak: 0, maxStreak: 1, hints: 2, distribution: []int{0, 1, 0, 0, 0, 0}}},
+		{"fewer rows than guesses", []gameRecord{record(6, 1), record(2, 1)}, 4, 1,
+			summary{played: 2, won: 2, currentStreak: 2, maxStreak: 2, hints: 2, distribution: []int{0, 1, 0, 0, 0, 1}}},
+		{"single board games only", []gameRecord{record(3, 1), record(7, 2), record(0, 2), record(4, 1)}, 6, 1,
+			summary{played: 2, won: 2, currentStreak: 2, maxStreak: 2, hints: 2, distribution: []int{0, 0, 1, 1, 0, 0}}},
+		{"multi-board games only", []gameRecord{record(3, 1), record(7, 2), record(0, 2), record(9, 4), record(6, 2)}, 7, 2,
+			summary{played: 3, won: 2, currentStreak: 1, maxStreak: 1, hints: 3, distribution: []int{0, 0, 0, 0, 0, 1, 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := stats{}
+			for _, r := range tt.games {
+				s.add(r)
+			}
+			if got := s.summarize(tt.rows, tt.boards); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("summarize(%d, %d) = %+v, want %+v", tt.rows, tt.boards, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWinPercent(t *testing.T) {
+	tests := []struct {
+		played int
+		won    int
+		want   int
+	}{
+		{0, 0, 0},
+		{1, 1, 100},
+		{3, 2, 66},
+		{3, 1, 33},
+		{7, 0, 0},
+	}
+	for _, tt := range tests {
+		s := summary{played: tt.played, won: tt.won}
+		if got := s.winPercent(); got != tt.want {
+			t.Errorf("winPercent() with %d won of %d = %d, want %d", tt.won, tt.played, got, tt.want)
+		}
+	}
+}
+
+func TestStatsSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "lexis", "stats.json")
+	s, err := loadStats(path)
+	if err != nil {
+		t.Fatalf("loadStats of a missing file: %v", err)
+	}
+	s.add(record(3, 1))
+	s.add(record(0, 2))
+	if err := s.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+	loaded, err := loadStats(path)
+	if err != nil {
+		t.Fatalf("loadStats: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, s) {
+		t.Errorf("loadStats() = %+v, want %+v", loaded, s)
+	}
+}
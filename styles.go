@@ -3,6 +3,7 @@ package main
 import (
 	tea "charm.land/bubbletea/v2"
 	"charm.land/lipgloss/v2"
+	"github.com/ieroNo47/lexis/engine"
 )
 
 // parent container style
@@ -11,91 +12,44 @@ var containerStyle = lipgloss.NewStyle().
 	Padding(0, 0, 0, 0).
 	Align(lipgloss.Center, lipgloss.Center)
 
-// styles for each state of a letter in the grid
-var defaultStyle = lipgloss.NewStyle().
-	Padding(0, 1).
-	Margin(0).
-	Border(lipgloss.RoundedBorder())
-
-var activeStyle = lipgloss.NewStyle().
-	Padding(0, 1).
-	Margin(0).
-	BorderForeground(lipgloss.Color("#88c0d0")).
-	Inherit(defaultStyle)
+// styles for each state of a letter in the grid, set by applyTheme
+var (
+	defaultStyle     lipgloss.Style
+	activeStyle      lipgloss.Style
+	exactMatchStyle  lipgloss.Style
+	existsMatchStyle lipgloss.Style
+	notMatchStyle    lipgloss.Style
+	// revealed letters are shown in the empty tiles of the active row until a letter is typed over them
+	revealedStyle lipgloss.Style
+)
 
-var exactMatchStyle = lipgloss.NewStyle().
-	Padding(0, 1).
-	Margin(0).
-	BorderForeground(lipgloss.Color("#a3be8c")).
-	Foreground(lipgloss.Color("#a3be8c")).
-	Inherit(defaultStyle)
+// match a state to a style
+var stateStyles map[engine.LetterState]lipgloss.Style
 
-var existsMatchStyle = lipgloss.NewStyle().
-	Padding(0, 1).
-	Margin(0).
-	BorderForeground(lipgloss.Color("#ebcb8b")).
-	Foreground(lipgloss.Color("#ebcb8b")).
-	Inherit(defaultStyle)
+// compact tile styles, used when several boards are shown at once
+var (
+	compactDefaultStyle lipgloss.Style
+	compactActiveStyle  lipgloss.Style
+	compactStyles       map[engine.LetterState]lipgloss.Style
+)
 
-var notMatchStyle = lipgloss.NewStyle().
-	Padding(0, 1).
-	Margin(0).
-	BorderForeground(lipgloss.Color("#4c566a")).
-	Foreground(lipgloss.Color("#4c566a")).
-	Inherit(defaultStyle)
+// split key colors, one cell per board on the keyboard of a multi-board game
+var splitKeyStyles map[engine.LetterState]lipgloss.Style
 
 // top bar style
-var headerStyle = lipgloss.NewStyle().
-	Padding(0).
-	Margin(0).
-	Align(lipgloss.Center).
-	Background(lipgloss.Color("#3b4252")).
-	Foreground(lipgloss.Color("#88c0d0"))
+var headerStyle lipgloss.Style
 
 // result bar styles
-var resultBarStyleNormal = lipgloss.NewStyle().
-	Padding(0).
-	Margin(0).
-	Align(lipgloss.Center).
-	Background(lipgloss.Color("#3b4252")).
-	Foreground(lipgloss.Color("#88c0d0"))
-
-var resultBarStyleWin = lipgloss.NewStyle().
-	Padding(0).
-	Margin(0).
-	Align(lipgloss.Center).
-	Background(lipgloss.Color("#a3be8c")).
-	Foreground(lipgloss.Color("#2e3440"))
-
-var resultBarStyleLoss = lipgloss.NewStyle().
-	Padding(0).
-	Margin(0).
-	Align(lipgloss.Center).
-	Background(lipgloss.Color("#ebcb8b")).
-	Foreground(lipgloss.Color("#2e3440"))
-
-var resultBarStyleLoading = lipgloss.NewStyle().
-	Padding(0).
-	Margin(0).
-	Align(lipgloss.Center).
-	Background(lipgloss.Color("#b48ead")).
-	Foreground(lipgloss.Color("#2e3440"))
-
-var resultBarStyleError = lipgloss.NewStyle().
-	Padding(0).
-	Margin(0).
-	Align(lipgloss.Center).
-	Background(lipgloss.Color("#bf616a")).
-	Foreground(lipgloss.Color("#2e3440"))
+var (
+	resultBarStyleNormal  lipgloss.Style
+	resultBarStyleWin     lipgloss.Style
+	resultBarStyleLoss    lipgloss.Style
+	resultBarStyleLoading lipgloss.Style
+	resultBarStyleError   lipgloss.Style
+)
 
 // status bar
-var helpBarStyle = lipgloss.NewStyle().
-	Padding(0).
-	Margin(0).
-	Align(lipgloss.Center).
-	Background(lipgloss.Color("#3b4252")).
-	Foreground(lipgloss.Color("#88c0d0")).
-	Faint(true)
+var helpBarStyle lipgloss.Style
 
 var helpTextStyle = lipgloss.NewStyle()
 
@@ -107,17 +61,151 @@ var defaultPopUpStyle = lipgloss.NewStyle().
 	Align(lipgloss.Center, lipgloss.Center).
 	Border(lipgloss.RoundedBorder())
 
-var popUpStyleWin = defaultPopUpStyle.
-	BorderForeground(lipgloss.Color("#a3be8c")).
-	Foreground(lipgloss.Color("#a3be8c"))
+// width at which long popup messages, such as errors, are wrapped
+const popupTextWidth = 50
+
+var (
+	popUpStyleWin   lipgloss.Style
+	popUpStyleLoss  lipgloss.Style
+	popUpStyleError lipgloss.Style
+	popUpStyleStats lipgloss.Style
+)
+
+// stats bar chart
+const statsBarWidth = 20 // width of the longest bar
+
+var statsBarStyle lipgloss.Style
+
+// barWidth is the width of the container and the bars, set from the terminal width by updateStyles
+var barWidth int
 
-var popUpStyleLoss = defaultPopUpStyle.
-	BorderForeground(lipgloss.Color("#ebcb8b")).
-	Foreground(lipgloss.Color("#ebcb8b"))
+// colorblind palette, orange and blue are told apart with any kind of red-green color blindness
+const (
+	colorblindMatched = "#f5793a"
+	colorblindExists  = "#85c0f9"
+)
+
+// applyTheme sets every style from the colors of a theme.
+// In colorblind mode the matched and misplaced letters use an orange and blue palette and are also marked by shape.
+func applyTheme(t theme, colorblind bool) {
+	if colorblind && !t.Monochrome {
+		t.Matched, t.Exists = colorblindMatched, colorblindExists
+	}
+	c := lipgloss.Color
+	tile := lipgloss.NewStyle().
+		Padding(0, 1).
+		Margin(0).
+		Border(lipgloss.RoundedBorder())
+	bar := lipgloss.NewStyle().
+		Padding(0).
+		Margin(0).
+		Align(lipgloss.Center)
+	compact := lipgloss.NewStyle().Padding(0, 1)
+	split := lipgloss.NewStyle()
+
+	defaultStyle = tile.BorderForeground(c(t.Tile))
+	activeStyle = tile.BorderForeground(c(t.Active))
+	exactMatchStyle = tile.BorderForeground(c(t.Matched)).Foreground(c(t.Matched))
+	existsMatchStyle = tile.BorderForeground(c(t.Exists)).Foreground(c(t.Exists))
+	notMatchStyle = tile.BorderForeground(c(t.NotMatched)).Foreground(c(t.NotMatched))
+	revealedStyle = tile.BorderForeground(c(t.Revealed)).Foreground(c(t.Revealed)).Faint(true)
+
+	compactDefaultStyle = compact.Background(c(t.Bar)).Foreground(c(t.Text))
+	compactActiveStyle = compactDefaultStyle.Background(c(t.NotMatched)).Underline(true)
+	compactStyles = map[engine.LetterState]lipgloss.Style{
+		engine.Matched:    compactDefaultStyle.Background(c(t.Matched)).Foreground(c(t.Dark)),
+		engine.Exists:     compactDefaultStyle.Background(c(t.Exists)).Foreground(c(t.Dark)),
+		engine.NotMatched: compactDefaultStyle.Background(c(t.Dark)).Foreground(c(t.NotMatched)),
+		engine.NotChecked: compactDefaultStyle,
+		engine.Revealed:   compactDefaultStyle.Foreground(c(t.Revealed)).Faint(true),
+	}
+	splitKeyStyles = map[engine.LetterState]lipgloss.Style{
+		engine.Matched:    split.Background(c(t.Matched)).Foreground(c(t.Dark)),
+		engine.Exists:     split.Background(c(t.Exists)).Foreground(c(t.Dark)),
+		engine.NotMatched: split.Background(c(t.Dark)).Foreground(c(t.NotMatched)),
+		engine.NotChecked: split.Background(c(t.NotMatched)).Foreground(c(t.Text)),
+	}
+
+	headerStyle = bar.Background(c(t.Bar)).Foreground(c(t.Header))
+	resultBarStyleNormal = bar.Background(c(t.Bar)).Foreground(c(t.Header))
+	resultBarStyleWin = bar.Background(c(t.Matched)).Foreground(c(t.Dark))
+	resultBarStyleLoss = bar.Background(c(t.Exists)).Foreground(c(t.Dark))
+	resultBarStyleLoading = bar.Background(c(t.Revealed)).Foreground(c(t.Dark))
+	resultBarStyleError = bar.Background(c(t.Error)).Foreground(c(t.Dark))
+	helpBarStyle = bar.Background(c(t.Bar)).Foreground(c(t.Help)).Faint(true)
+
+	popUpStyleWin = defaultPopUpStyle.BorderForeground(c(t.Matched)).Foreground(c(t.Matched))
+	popUpStyleLoss = defaultPopUpStyle.BorderForeground(c(t.Exists)).Foreground(c(t.Exists))
+	popUpStyleError = defaultPopUpStyle.BorderForeground(c(t.Error)).Foreground(c(t.Error))
+	popUpStyleStats = defaultPopUpStyle.Padding(1, 2).BorderForeground(c(t.Popup)).Foreground(c(t.Popup))
+
+	statsBarStyle = lipgloss.NewStyle().Background(c(t.Chart))
+
+	if t.Monochrome {
+		applyMonochrome()
+	}
+	if colorblind {
+		applyColorblindMarkers()
+	}
+	stateStyles = map[engine.LetterState]lipgloss.Style{
+		engine.Matched:    exactMatchStyle,
+		engine.Exists:     existsMatchStyle,
+		engine.NotMatched: notMatchStyle,
+		engine.NotChecked: defaultStyle,
+		engine.Revealed:   revealedStyle,
+	}
+	setBarWidth()
+}
+
+// applyMonochrome tells the states apart with text attributes, for themes without colors
+func applyMonochrome() {
+	exactMatchStyle = exactMatchStyle.Bold(true).Reverse(true)
+	existsMatchStyle = existsMatchStyle.Underline(true)
+	notMatchStyle = notMatchStyle.Faint(true)
+	activeStyle = activeStyle.Bold(true)
+	compactActiveStyle = compactActiveStyle.Bold(true)
+	for state, style := range map[engine.LetterState]lipgloss.Style{
+		engine.Matched:    compactStyles[engine.Matched].Bold(true).Reverse(true),
+		engine.Exists:     compactStyles[engine.Exists].Underline(true),
+		engine.NotMatched: compactStyles[engine.NotMatched].Faint(true),
+	} {
+		compactStyles[state] = style
+		splitKeyStyles[state] = splitKeyStyles[state].Inherit(style)
+	}
+	headerStyle = headerStyle.Reverse(true)
+	resultBarStyleNormal = resultBarStyleNormal.Reverse(true)
+	resultBarStyleWin = resultBarStyleWin.Reverse(true).Bold(true)
+	resultBarStyleLoss = resultBarStyleLoss.Reverse(true).Underline(true)
+	resultBarStyleLoading = resultBarStyleLoading.Reverse(true)
+	resultBarStyleError = resultBarStyleError.Reverse(true).Bold(true)
+	popUpStyleWin = popUpStyleWin.Bold(true)
+	popUpStyleError = popUpStyleError.Bold(true)
+	statsBarStyle = statsBarStyle.Reverse(true)
+}
 
-var popUpStyleError = defaultPopUpStyle.
-	BorderForeground(lipgloss.Color("#bf616a")).
-	Foreground(lipgloss.Color("#bf616a"))
+// applyColorblindMarkers tells matched and misplaced letters apart by shape as well as by color:
+// double borders and [x] for matched letters, square borders with the letter underlined and (x) for misplaced
+// letters, and = or ~ in the cells of split keys
+func applyColorblindMarkers() {
+	exactMatchStyle = exactMatchStyle.Border(lipgloss.DoubleBorder()).Bold(true)
+	existsMatchStyle = existsMatchStyle.Border(lipgloss.NormalBorder()).Underline(true)
+	compactStyles[engine.Matched] = compactStyles[engine.Matched].Padding(0).Bold(true).
+		Transform(func(s string) string { return "[" + s + "]" })
+	compactStyles[engine.Exists] = compactStyles[engine.Exists].Padding(0).Underline(true).
+		Transform(func(s string) string { return "(" + s + ")" })
+	splitKeyStyles[engine.Matched] = splitKeyStyles[engine.Matched].Transform(splitKeyMarker("="))
+	splitKeyStyles[engine.Exists] = splitKeyStyles[engine.Exists].Transform(splitKeyMarker("~"))
+}
+
+// splitKeyMarker returns a transform that shows marker in the empty cells of a split key, keeping the letter
+func splitKeyMarker(marker string) func(string) string {
+	return func(s string) string {
+		if s == " " {
+			return marker
+		}
+		return s
+	}
+}
 
 func updateStyles(msg tea.WindowSizeMsg) int {
 	// oVertical := containerStyle.GetBorderTopSize() +
@@ -131,13 +219,19 @@ func updateStyles(msg tea.WindowSizeMsg) int {
 		containerStyle.GetMarginRight()
 
 	// size of the parent container adjusted to be the window size - the size of the borders and margins
-	containerStyle = containerStyle.Width(msg.Width - oHorizontal)
-	headerStyle = headerStyle.Width(msg.Width - oHorizontal)
-	resultBarStyleNormal = resultBarStyleNormal.Width(msg.Width - oHorizontal)
-	resultBarStyleWin = resultBarStyleWin.Width(msg.Width - oHorizontal)
-	resultBarStyleLoss = resultBarStyleLoss.Width(msg.Width - oHorizontal)
-	resultBarStyleLoading = resultBarStyleLoading.Width(msg.Width - oHorizontal)
-	resultBarStyleError = resultBarStyleError.Width(msg.Width - oHorizontal)
-	helpBarStyle = helpBarStyle.Width(msg.Width - oHorizontal)
+	barWidth = msg.Width - oHorizontal
+	setBarWidth()
 	return oHorizontal
 }
+
+// setBarWidth sets the width of the container and the bars, so they span the terminal
+func setBarWidth() {
+	containerStyle = containerStyle.Width(barWidth)
+	headerStyle = headerStyle.Width(barWidth)
+	resultBarStyleNormal = resultBarStyleNormal.Width(barWidth)
+	resultBarStyleWin = resultBarStyleWin.Width(barWidth)
+	resultBarStyleLoss = resultBarStyleLoss.Width(barWidth)
+	resultBarStyleLoading = resultBarStyleLoading.Width(barWidth)
+	resultBarStyleError = resultBarStyleError.Width(barWidth)
+	helpBarStyle = helpBarStyle.Width(barWidth)
+}
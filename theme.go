@@ -0,0 +1,154 @@
+// theme.go defines the color themes, built-in and user-defined
+package main
+
+import (
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+)
+
+// default color theme
+const defaultTheme = "nord"
+
+// theme is a color scheme. Colors are hex values such as #88c0d0 or ANSI color numbers from 0 to 255,
+// an empty color uses the terminal's default.
+type theme struct {
+	Base       string `json:"base,omitempty"` // built-in theme a user-defined theme starts from, nord if empty
+	Matched    string `json:"matched"`        // letters in the right position, win bar and popup
+	Exists     string `json:"exists"`         // letters in the wrong position, loss bar and popup
+	NotMatched string `json:"notMatched"`     // letters not in the word
+	Revealed   string `json:"revealed"`       // letters revealed by hints, loading bar
+	Tile       string `json:"tile"`           // border of the empty tiles
+	Active     string `json:"active"`         // border of the active tile
+	Header     string `json:"header"`         // text of the header and result bar
+	Bar        string `json:"bar"`            // background of the header, result bar and help bar, and of the compact tiles
+	Help       string `json:"help"`           // text of the help bar
+	Popup      string `json:"popup"`          // text and border of the info popups, such as stats and hints
+	Text       string `json:"text"`           // text of the compact tiles and split keys
+	Dark       string `json:"dark"`           // text on colored backgrounds
+	Error      string `json:"error"`          // error bar and popup
+	Chart      string `json:"chart"`          // bars of the stats chart
+	// Monochrome tells the states apart with text attributes instead of colors:
+	// reverse for matched letters, underline for letters in the wrong position and faint for absent letters
+	Monochrome bool `json:"monochrome,omitempty"`
+}
+
+// builtinThemes holds the themes shipped with lexis
+var builtinThemes = map[string]theme{
+	"nord": {
+		Matched:    "#a3be8c",
+		Exists:     "#ebcb8b",
+		NotMatched: "#4c566a",
+		Revealed:   "#b48ead",
+		Active:     "#88c0d0",
+		Header:     "#88c0d0",
+		Bar:        "#3b4252",
+		Help:       "#88c0d0",
+		Popup:      "#88c0d0",
+		Text:       "#eceff4",
+		Dark:       "#2e3440",
+		Error:      "#bf616a",
+		Chart:      "#5e81ac",
+	},
+	"solarized": {
+		Matched:    "#859900",
+		Exists:     "#b58900",
+		NotMatched: "#586e75",
+		Revealed:   "#6c71c4",
+		Active:     "#268bd2",
+		Header:     "#2aa198",
+		Bar:        "#073642",
+		Help:       "#93a1a1",
+		Popup:      "#2aa198",
+		Text:       "#93a1a1",
+		Dark:       "#002b36",
+		Error:      "#dc322f",
+		Chart:      "#268bd2",
+	},
+	"gruvbox": {
+		Matched:    "#b8bb26",
+		Exists:     "#fabd2f",
+		NotMatched: "#665c54",
+		Revealed:   "#d3869b",
+		Active:     "#83a598",
+		Header:     "#8ec07c",
+		Bar:        "#3c3836",
+		Help:       "#a89984",
+		Popup:      "#8ec07c",
+		Text:       "#ebdbb2",
+		Dark:       "#282828",
+		Error:      "#fb4934",
+		Chart:      "#83a598",
+	},
+	"high-contrast": {
+		Matched:    "#00ff00",
+		Exists:     "#ffff00",
+		NotMatched: "#808080",
+		Revealed:   "#ff00ff",
+		Tile:       "#ffffff",
+		Active:     "#00ffff",
+		Header:     "#ffffff",
+		Bar:        "#000000",
+		Help:       "#ffffff",
+		Popup:      "#ffffff",
+		Text:       "#ffffff",
+		Dark:       "#000000",
+		Error:      "#ff0000",
+		Chart:      "#00ffff",
+	},
+	"mono": {
+		Monochrome: true,
+	},
+}
+
+// hex colors, #rgb or #rrggbb
+var hexColor = regexp.MustCompile(`^#([0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+// validateTheme checks that a user-defined theme has a known base and only valid colors
+func validateTheme(t theme) error {
+	if _, ok := builtinThemes[t.Base]; t.Base != "" && !ok {
+		return fmt.Errorf("unknown base theme %q, expected one of %v", t.Base, builtinThemeNames())
+	}
+	for _, c := range t.colors() {
+		if *c == "" || hexColor.MatchString(*c) {
+			continue
+		}
+		if n, err := strconv.Atoi(*c); err == nil && n >= 0 && n < 256 {
+			continue
+		}
+		return fmt.Errorf("invalid color %q, expected #rrggbb or an ANSI color number", *c)
+	}
+	return nil
+}
+
+// colors returns pointers to every color of the theme
+func (t *theme) colors() []*string {
+	return []*string{
+		&t.Matched, &t.Exists, &t.NotMatched, &t.Revealed, &t.Tile, &t.Active, &t.Header,
+		&t.Bar, &t.Help, &t.Popup, &t.Text, &t.Dark, &t.Error, &t.Chart,
+	}
+}
+
+// resolve returns a user-defined theme with the colors it leaves empty taken from its base theme
+func (t theme) resolve() theme {
+	base := builtinThemes[defaultTheme]
+	if b, ok := builtinThemes[t.Base]; ok {
+		base = b
+	}
+	baseColors := base.colors()
+	for i, c := range t.colors() {
+		if *c == "" {
+			*c = *baseColors[i]
+		}
+	}
+	t.Monochrome = t.Monochrome || base.Monochrome
+	t.Base = ""
+	return t
+}
+
+// builtinThemeNames returns the names of the built-in themes in alphabetical order
+func builtinThemeNames() []string {
+	return slices.Sorted(maps.Keys(builtinThemes))
+}